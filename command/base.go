@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/privateerproj/privateer-sdk/config"
 )
 
 // SetBase sets the flags that are universal to every command. These are safe to
@@ -40,22 +42,43 @@ func SetRunFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP("test-suites", "t", "default", "Named set of test sets to execute from the plugin")
 	_ = viper.BindPFlag("test-suites", cmd.PersistentFlags().Lookup("test-suites"))
 
+	cmd.PersistentFlags().StringSliceP("include", "", nil, "Only assess matching requirements: control ids, requirement ids, or control families; globs allowed")
+	_ = viper.BindPFlag("include", cmd.PersistentFlags().Lookup("include"))
+
+	cmd.PersistentFlags().StringSliceP("exclude", "", nil, "Skip matching requirements: control ids, requirement ids, or control families; globs allowed")
+	_ = viper.BindPFlag("exclude", cmd.PersistentFlags().Lookup("exclude"))
+
 	cmd.PersistentFlags().BoolP("silent", "", false, "Only show essential log information")
 	_ = viper.BindPFlag("silent", cmd.PersistentFlags().Lookup("silent"))
 
 	cmd.PersistentFlags().BoolP("write", "", true, "Keep all of the detailed result outputs in a file. Disabling does not disable log files")
 	_ = viper.BindPFlag("write", cmd.PersistentFlags().Lookup("write"))
 
-	cmd.PersistentFlags().StringP("output", "o", "yaml", "Output format for results: yaml, json, sarif, or gemara")
+	cmd.PersistentFlags().StringSliceP("output", "o", []string{"yaml"}, "Output formats for results, comma-separated or repeated: yaml, json, sarif, gemara, html, junit, or oscal")
 	_ = viper.BindPFlag("output", cmd.PersistentFlags().Lookup("output"))
 
+	cmd.PersistentFlags().StringP("output-destination", "", "file", "Where results are written: file (the write directory), or stdout (also -) to stream a single output type")
+	_ = viper.BindPFlag("output-destination", cmd.PersistentFlags().Lookup("output-destination"))
+
 	cmd.PersistentFlags().BoolP("include-payload", "", false, "Include the raw evaluated payload in results output (large; useful for tracing)")
 	_ = viper.BindPFlag("include-payload", cmd.PersistentFlags().Lookup("include-payload"))
+
+	cmd.PersistentFlags().StringP("record-payload", "", "", "Save the loaded payloads to this fixture file")
+	_ = viper.BindPFlag("record-payload", cmd.PersistentFlags().Lookup("record-payload"))
+
+	cmd.PersistentFlags().StringP("replay-payload", "", "", "Skip the loaders and replay the payloads saved in this fixture file")
+	_ = viper.BindPFlag("replay-payload", cmd.PersistentFlags().Lookup("replay-payload"))
+
+	cmd.PersistentFlags().DurationP("payload-cache-ttl", "", 0, "Reuse cached loader payloads younger than this (e.g. 30m); 0 disables the cache")
+	_ = viper.BindPFlag("payload-cache-ttl", cmd.PersistentFlags().Lookup("payload-cache-ttl"))
 }
 
 // ReadConfig reads the configuration file. If --config is explicitly provided,
 // that exact path is used. Otherwise, it searches ./config.yml and ~/.privateer/config.yml.
 func ReadConfig() {
+	// Keep YAML dates as written, so waiver expiries keep their meaning.
+	viper.SetOptions(viper.WithCodecRegistry(config.CodecRegistry()))
+
 	// Namespace env overrides so only PVTR_* vars are recognized,
 	// preventing accidental or malicious collisions on shared systems.
 	viper.SetEnvPrefix("PVTR")
@@ -14,13 +14,19 @@ var universalFlags = []string{"config", "loglevel"}
 // They must not leak onto the shared root, where their shorthands can collide
 // with sibling subcommands (e.g. -o vs generate-plugin's --output-dir).
 var runScopedFlags = map[string]string{
-	"output":          "o",
-	"write-directory": "w",
-	"service":         "s",
-	"test-suites":     "t",
-	"silent":          "",
-	"write":           "",
-	"include-payload": "",
+	"output":             "o",
+	"write-directory":    "w",
+	"service":            "s",
+	"test-suites":        "t",
+	"include":            "",
+	"exclude":            "",
+	"silent":             "",
+	"write":              "",
+	"output-destination": "",
+	"include-payload":    "",
+	"record-payload":     "",
+	"replay-payload":     "",
+	"payload-cache-ttl":  "",
 }
 
 // TestSetBase_RegistersUniversalFlags asserts SetBase keeps the truly universal
@@ -0,0 +1,230 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/spf13/cobra"
+)
+
+// ResultDiff compares the assessments of two result documents. Requirements
+// are matched by catalog, control and requirement id. Only Regressed fails a
+// diff; every other change is reported for information.
+type ResultDiff struct {
+	Baseline string `json:"baseline"`
+	Current  string `json:"current"`
+	// Regressed were not Failed in the baseline, or not in it at all, and
+	// are Failed now, so a newly added failing requirement fails the diff.
+	Regressed []RequirementChange `json:"regressed"`
+	// Fixed were not Passed in the baseline and are Passed now.
+	Fixed []RequirementChange `json:"fixed"`
+	// Changed moved between any other pair of results, e.g. Passed to Needs Review.
+	Changed []RequirementChange `json:"changed"`
+	// Added are new in the current run and not Failed.
+	Added   []RequirementChange `json:"added"`
+	Removed []RequirementChange `json:"removed"`
+}
+
+// RequirementChange is one requirement whose outcome differs between runs.
+// Before is empty for a requirement new in the current run and After for a
+// removed one.
+type RequirementChange struct {
+	Catalog     string `json:"catalog"`
+	Control     string `json:"control"`
+	Requirement string `json:"requirement"`
+	Before      string `json:"before,omitempty"`
+	After       string `json:"after,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type requirementKey struct {
+	catalog, control, requirement string
+}
+
+type requirementOutcome struct {
+	result  gemara.Result
+	message string
+}
+
+// diffCmd returns the `pvtr diff` command. It reads two results files written
+// by any plugin (yaml or json envelope, or output: gemara) and exits non-zero
+// only when a requirement has regressed to Failed, including one that is new
+// in the current run.
+func diffCmd(writerFn func() Writer) *cobra.Command {
+	var jsonOut bool
+
+	diffCmd := &cobra.Command{
+		Use:   "diff <baseline-results> <current-results>",
+		Short: "Compare two evaluation results files and report what regressed.",
+		Long: "Load two results files (the yaml or json orchestrator output, or the gemara " +
+			"EvaluationLog list), match their assessments by catalog, control and requirement " +
+			"id, and report requirements that are newly failing, newly passing, otherwise " +
+			"changed, added or removed.\n\n" +
+			"The command fails only when a requirement regressed to Failed, so it can gate " +
+			"a nightly run against the previous night's results. A requirement that is new " +
+			"in the current run and Failed counts as regressed, not added.",
+		Args: cobra.ExactArgs(2),
+		// runtime failures shouldn't reprint usage text
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diff, err := diffResultFiles(args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				data, err := json.MarshalIndent(diff, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshaling results diff: %w", err)
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			} else {
+				w := writerFn()
+				renderResultDiff(w, diff)
+				_ = w.Flush()
+			}
+			if n := len(diff.Regressed); n > 0 {
+				return fmt.Errorf("%d requirement(s) regressed to %s", n, gemara.Failed)
+			}
+			return nil
+		},
+	}
+	diffCmd.Flags().BoolVar(&jsonOut, "json", false, "Emit the diff as JSON")
+	return diffCmd
+}
+
+// diffResultFiles loads both files and compares them.
+func diffResultFiles(baselinePath, currentPath string) (ResultDiff, error) {
+	baseline, err := loadOutcomes(baselinePath)
+	if err != nil {
+		return ResultDiff{}, err
+	}
+	current, err := loadOutcomes(currentPath)
+	if err != nil {
+		return ResultDiff{}, err
+	}
+	diff := diffOutcomes(baseline, current)
+	diff.Baseline, diff.Current = baselinePath, currentPath
+	return diff, nil
+}
+
+// loadOutcomes reads a results file into one outcome per requirement.
+func loadOutcomes(path string) (map[requirementKey]requirementOutcome, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading results: %w", err)
+	}
+	results, err := parseResults(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing results %s: %w", path, err)
+	}
+	outcomes := make(map[requirementKey]requirementOutcome)
+	for _, suite := range results.EvaluationSuites {
+		for _, evaluation := range suite.EvaluationLog.Evaluations {
+			catalog := suite.CatalogId
+			if catalog == "" {
+				catalog = evaluation.Control.ReferenceId
+			}
+			for _, log := range evaluation.AssessmentLogs {
+				key := requirementKey{catalog: catalog, control: evaluation.Control.EntryId, requirement: log.Requirement.EntryId}
+				outcomes[key] = requirementOutcome{result: log.Result, message: log.Message}
+			}
+		}
+	}
+	if len(outcomes) == 0 {
+		return nil, fmt.Errorf("no assessments found in %s", path)
+	}
+	return outcomes, nil
+}
+
+// diffOutcomes classifies every requirement present in either run. Each list
+// is sorted by catalog, control and requirement id.
+func diffOutcomes(baseline, current map[requirementKey]requirementOutcome) ResultDiff {
+	diff := ResultDiff{
+		Regressed: []RequirementChange{},
+		Fixed:     []RequirementChange{},
+		Changed:   []RequirementChange{},
+		Added:     []RequirementChange{},
+		Removed:   []RequirementChange{},
+	}
+	for key, after := range current {
+		change := RequirementChange{Catalog: key.catalog, Control: key.control, Requirement: key.requirement, After: after.result.String(), Message: after.message}
+		before, ok := baseline[key]
+		switch {
+		case !ok && after.result == gemara.Failed:
+			diff.Regressed = append(diff.Regressed, change)
+		case !ok:
+			diff.Added = append(diff.Added, change)
+		case before.result == after.result:
+		case after.result == gemara.Failed:
+			change.Before = before.result.String()
+			diff.Regressed = append(diff.Regressed, change)
+		case after.result == gemara.Passed:
+			change.Before = before.result.String()
+			diff.Fixed = append(diff.Fixed, change)
+		default:
+			change.Before = before.result.String()
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for key, before := range baseline {
+		if _, ok := current[key]; !ok {
+			diff.Removed = append(diff.Removed, RequirementChange{Catalog: key.catalog, Control: key.control, Requirement: key.requirement, Before: before.result.String()})
+		}
+	}
+	for _, changes := range [][]RequirementChange{diff.Regressed, diff.Fixed, diff.Changed, diff.Added, diff.Removed} {
+		sortChanges(changes)
+	}
+	return diff
+}
+
+func sortChanges(changes []RequirementChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Catalog != b.Catalog {
+			return a.Catalog < b.Catalog
+		}
+		if a.Control != b.Control {
+			return a.Control < b.Control
+		}
+		return a.Requirement < b.Requirement
+	})
+}
+
+// renderResultDiff prints a section per kind of change, omitting empty ones.
+func renderResultDiff(w Writer, diff ResultDiff) {
+	_, _ = fmt.Fprintf(w, "Diff: %s -> %s\n", diff.Baseline, diff.Current)
+	_, _ = fmt.Fprintf(w, "%d regressed, %d fixed, %d changed, %d added, %d removed\n",
+		len(diff.Regressed), len(diff.Fixed), len(diff.Changed), len(diff.Added), len(diff.Removed))
+	sections := []struct {
+		title   string
+		changes []RequirementChange
+	}{
+		{"Newly failing", diff.Regressed},
+		{"Newly passing", diff.Fixed},
+		{"Changed", diff.Changed},
+		{"Added", diff.Added},
+		{"Removed", diff.Removed},
+	}
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n%s:\n", section.title)
+		_, _ = fmt.Fprintln(w, "CATALOG\tCONTROL\tREQUIREMENT\tBEFORE\tAFTER\tMESSAGE")
+		for _, c := range section.changes {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				c.Catalog, c.Control, c.Requirement, orDash(c.Before), orDash(c.After), c.Message)
+		}
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
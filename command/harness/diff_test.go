@@ -0,0 +1,181 @@
+package harness
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+const baselineResults = `service-name: svc-a
+evaluation-suites:
+  - catalog-id: CCC.ObjStor
+    control-evaluations:
+      evaluations:
+        - control:
+            reference-id: CCC.ObjStor
+            entry-id: CCC.ObjStor.C01
+          assessment-logs:
+            - requirement: {entry-id: CCC.ObjStor.C01.TR01}
+              result: Passed
+            - requirement: {entry-id: CCC.ObjStor.C01.TR02}
+              result: Failed
+            - requirement: {entry-id: CCC.ObjStor.C01.TR03}
+              result: Passed
+            - requirement: {entry-id: CCC.ObjStor.C01.TR04}
+              result: Passed
+            - requirement: {entry-id: CCC.ObjStor.C01.TR05}
+              result: Needs Review
+`
+
+// currentResults is the gemara list shape, so a diff across output formats is
+// covered too.
+const currentResults = `- evaluations:
+    - control:
+        reference-id: CCC.ObjStor
+        entry-id: CCC.ObjStor.C01
+      assessment-logs:
+        - requirement: {entry-id: CCC.ObjStor.C01.TR01}
+          result: Failed
+          message: bucket is public
+        - requirement: {entry-id: CCC.ObjStor.C01.TR02}
+          result: Passed
+        - requirement: {entry-id: CCC.ObjStor.C01.TR03}
+          result: Needs Review
+        - requirement: {entry-id: CCC.ObjStor.C01.TR05}
+          result: Needs Review
+        - requirement: {entry-id: CCC.ObjStor.C01.TR06}
+          result: Passed
+`
+
+func writeDiffFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiffResultFiles(t *testing.T) {
+	diff, err := diffResultFiles(writeDiffFile(t, "old.yaml", baselineResults), writeDiffFile(t, "new.yaml", currentResults))
+	if err != nil {
+		t.Fatalf("diffResultFiles failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		changes []RequirementChange
+		want    string
+	}{
+		{"regressed", diff.Regressed, "CCC.ObjStor.C01.TR01 Passed->Failed"},
+		{"fixed", diff.Fixed, "CCC.ObjStor.C01.TR02 Failed->Passed"},
+		{"changed", diff.Changed, "CCC.ObjStor.C01.TR03 Passed->Needs Review"},
+		{"added", diff.Added, "CCC.ObjStor.C01.TR06 ->Passed"},
+		{"removed", diff.Removed, "CCC.ObjStor.C01.TR04 Passed->"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.changes) != 1 {
+				t.Fatalf("expected one change, got %+v", tt.changes)
+			}
+			c := tt.changes[0]
+			if got := c.Requirement + " " + c.Before + "->" + c.After; got != tt.want || c.Catalog != "CCC.ObjStor" || c.Control != "CCC.ObjStor.C01" {
+				t.Errorf("got %q (%+v), want %q", got, c, tt.want)
+			}
+		})
+	}
+	if diff.Regressed[0].Message != "bucket is public" {
+		t.Errorf("expected the current message on a regression, got %q", diff.Regressed[0].Message)
+	}
+}
+
+func TestDiffOutcomes_NewRequirements(t *testing.T) {
+	key := func(requirement string) requirementKey {
+		return requirementKey{catalog: "CCC.ObjStor", control: "CCC.ObjStor.C01", requirement: requirement}
+	}
+	baseline := map[requirementKey]requirementOutcome{key("TR01"): {result: gemara.Passed}}
+	tests := []struct {
+		name          string
+		result        gemara.Result
+		wantRegressed int
+		wantAdded     int
+	}{
+		{name: "a new failure regresses", result: gemara.Failed, wantRegressed: 1},
+		{name: "a new pass is added", result: gemara.Passed, wantAdded: 1},
+		{name: "a new needs review is added", result: gemara.NeedsReview, wantAdded: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := map[requirementKey]requirementOutcome{
+				key("TR01"): {result: gemara.Passed},
+				key("TR02"): {result: tt.result},
+			}
+			diff := diffOutcomes(baseline, current)
+			if len(diff.Regressed) != tt.wantRegressed || len(diff.Added) != tt.wantAdded {
+				t.Fatalf("got %d regressed and %d added, want %d and %d", len(diff.Regressed), len(diff.Added), tt.wantRegressed, tt.wantAdded)
+			}
+			if tt.wantRegressed > 0 && (diff.Regressed[0].Before != "" || diff.Regressed[0].Requirement != "TR02") {
+				t.Errorf("expected the new requirement regressed from nothing, got %+v", diff.Regressed[0])
+			}
+		})
+	}
+}
+
+func TestDiffCmd(t *testing.T) {
+	baseline := writeDiffFile(t, "old.yaml", baselineResults)
+	current := writeDiffFile(t, "new.yaml", currentResults)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+		wantOut string
+	}{
+		{name: "regressions fail", args: []string{baseline, current}, wantErr: "1 requirement(s) regressed to Failed", wantOut: "Newly failing:"},
+		{name: "unchanged passes", args: []string{current, current}, wantOut: "0 regressed"},
+		{name: "json output", args: []string{"--json", current, current}, wantOut: `"regressed": []`},
+		{name: "unreadable file", args: []string{baseline, filepath.Join(t.TempDir(), "missing.yaml")}, wantErr: "reading results"},
+		{name: "no assessments", args: []string{baseline, writeDiffFile(t, "empty.yaml", "evaluation-suites: []\n")}, wantErr: "no assessments found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bufWriter{}
+			cmd := diffCmd(func() Writer { return out })
+			cmd.SetOut(out)
+			cmd.SetErr(out)
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("expected %q in output, got:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestDiffCmd_JSONIsParseable(t *testing.T) {
+	out := &bufWriter{}
+	cmd := diffCmd(func() Writer { return out })
+	cmd.SetOut(out)
+	cmd.SetErr(io.Discard) // the regression error is not part of the json
+	cmd.SetArgs([]string{"--json", writeDiffFile(t, "old.yaml", baselineResults), writeDiffFile(t, "new.yaml", currentResults)})
+	_ = cmd.Execute()
+
+	var diff ResultDiff
+	if err := json.Unmarshal(out.Bytes(), &diff); err != nil {
+		t.Fatalf("json output did not parse: %v\n%s", err, out.String())
+	}
+	if len(diff.Regressed) != 1 || len(diff.Removed) != 1 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+}
@@ -31,4 +31,10 @@ func SetHarnessFlags(cmd *cobra.Command) {
 	// PVTR_AUTOINSTALL environment variable.
 	cmd.PersistentFlags().Bool("autoinstall", false, "Before a run, install any config-requested plugins that are not yet installed")
 	_ = viper.BindPFlag("autoinstall", cmd.PersistentFlags().Lookup("autoinstall"))
+
+	// parallel-plugins: how many services a `pvtr run` executes at once (see
+	// config.ParallelPlugins). Also settable via the parallel-plugins config.yml
+	// key or the PVTR_PARALLEL_PLUGINS environment variable.
+	cmd.PersistentFlags().Int("parallel-plugins", 1, "Number of plugins a run executes concurrently; output is grouped per plugin")
+	_ = viper.BindPFlag("parallel-plugins", cmd.PersistentFlags().Lookup("parallel-plugins"))
 }
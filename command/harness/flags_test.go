@@ -11,7 +11,7 @@ func TestSetHarnessFlags(t *testing.T) {
 	cmd := &cobra.Command{}
 	SetHarnessFlags(cmd)
 
-	for _, name := range []string{"hub-url", "autoinstall"} {
+	for _, name := range []string{"hub-url", "autoinstall", "parallel-plugins"} {
 		if cmd.PersistentFlags().Lookup(name) == nil {
 			t.Errorf("expected --%s flag to be registered", name)
 		}
@@ -22,4 +22,7 @@ func TestSetHarnessFlags(t *testing.T) {
 	if got := cmd.PersistentFlags().Lookup("autoinstall").DefValue; got != "false" {
 		t.Errorf("--autoinstall default = %q, want false", got)
 	}
+	if got := cmd.PersistentFlags().Lookup("parallel-plugins").DefValue; got != "1" {
+		t.Errorf("--parallel-plugins default = %q, want 1", got)
+	}
 }
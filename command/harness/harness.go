@@ -29,11 +29,15 @@ package harness
 import (
 	"context"
 	"fmt"
+	"os"
+	"text/tabwriter"
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/privateerproj/privateer-sdk/command"
+	"github.com/privateerproj/privateer-sdk/config"
 	"github.com/privateerproj/privateer-sdk/shared"
 )
 
@@ -99,6 +103,16 @@ func GetBenchmarkCmd(writerFn func() Writer) *cobra.Command {
 	return benchmarkCmd(writerFn)
 }
 
+// GetDiffCmd returns the `pvtr diff` command.
+func GetDiffCmd(writerFn func() Writer) *cobra.Command {
+	return diffCmd(writerFn)
+}
+
+// GetPlanCmd returns the `pvtr plan` command.
+func GetPlanCmd(writerFn func() Writer) *cobra.Command {
+	return planCmd(writerFn)
+}
+
 // GeneratePlugin forwards to command.GeneratePlugin.
 func GeneratePlugin(logger hclog.Logger) (exitCode int) {
 	return command.GeneratePlugin(logger) //nolint:staticcheck // intentional forwarding during migration
@@ -114,6 +128,12 @@ func GeneratePlugin(logger hclog.Logger) (exitCode int) {
 // than a convention each caller must remember; it is a no-op when autoinstall is
 // disabled, leaving the usual "not installed" failure.
 //
+// Once the plugins have run, a run summary (one row per requested service,
+// built from the plugin state and the results each plugin wrote) is printed to
+// w and, unless writing is disabled, saved as summary.json in the write
+// directory. When plugins stream their results to stdout the summary goes to
+// stderr instead. A run that exits before executing any plugin prints no summary.
+//
 // ctx bounds the preflight's hub/registry calls. w receives install progress and
 // is flushed before plugins start. logger and getPlugins drive the run loop.
 func Run(ctx context.Context, w Writer, logger hclog.Logger, getPlugins func() []*PluginPkg) (exitCode int) {
@@ -123,7 +143,41 @@ func Run(ctx context.Context, w Writer, logger hclog.Logger, getPlugins func() [
 		return command.BadUsage
 	}
 	_ = w.Flush()
-	return command.Run(logger, getPlugins) //nolint:staticcheck // intentional forwarding during migration
+
+	plugins := getPlugins()
+	exitCode = command.Run(logger, func() []*PluginPkg { return plugins }) //nolint:staticcheck // intentional forwarding during migration
+	if !anyStarted(plugins) {
+		return exitCode
+	}
+
+	writeDir := viper.GetString("write-directory")
+	summary := buildRunSummary(plugins, exitCode, writeDir)
+	summaryOut := w
+	if config.StreamsResults() {
+		// stdout carries the plugins' results; keep it parseable
+		summaryOut = tabwriter.NewWriter(os.Stderr, 1, 1, 1, ' ', 0)
+	}
+	renderRunSummary(summaryOut, summary)
+	_ = summaryOut.Flush()
+	if viper.GetBool("write") && writeDir != "" {
+		summaryPath, err := writeRunSummary(summary, writeDir)
+		if err != nil {
+			logger.Error(fmt.Sprintf("run summary was not saved: %s", err))
+		} else {
+			logger.Trace(fmt.Sprintf("Run summary written to %s", summaryPath))
+		}
+	}
+	return exitCode
+}
+
+// anyStarted reports whether command.Run got as far as starting a plugin.
+func anyStarted(plugins []*PluginPkg) bool {
+	for _, p := range plugins {
+		if p.Duration > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // GetPlugins forwards to command.GetPlugins.
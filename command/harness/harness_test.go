@@ -28,6 +28,12 @@ func TestConstructorsForward(t *testing.T) {
 	if GetLogoutCmd(writerFn) == nil {
 		t.Error("GetLogoutCmd returned nil")
 	}
+	if GetDiffCmd(writerFn) == nil {
+		t.Error("GetDiffCmd returned nil")
+	}
+	if GetPlanCmd(writerFn) == nil {
+		t.Error("GetPlanCmd returned nil")
+	}
 }
 
 // TestTypeAliasIdentity confirms the aliases are identity-preserving: a
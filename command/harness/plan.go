@@ -0,0 +1,119 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/privateerproj/privateer-sdk/command"
+	"github.com/privateerproj/privateer-sdk/pluginkit"
+)
+
+// planExecTimeout bounds each plugin's plan subcommand. Planning only reads
+// config and catalogs, so this only guards against a hung process.
+const planExecTimeout = 30 * time.Second
+
+// planCmd returns the `pvtr plan` command. It asks each requested plugin what
+// a run of its service would execute, without calling any loader or step.
+func planCmd(writerFn func() Writer) *cobra.Command {
+	var jsonOut bool
+
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show what `pvtr run` would execute for each service, without running it.",
+		Long: "Resolve config, policy, applicability, filters and test suites for every " +
+			"requested service and print, per service, the catalogs matched, the requirements " +
+			"selected, the steps registered for each, requirements with no steps, and the " +
+			"invasive changes that could be applied. No plugin loads data or runs a step.",
+		Args: cobra.NoArgs,
+		// runtime failures shouldn't reprint usage text
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			plans, err := planPlugins(cmd.Context(), command.GetPlugins())
+			if jsonOut {
+				data, marshalErr := json.MarshalIndent(plans, "", "  ")
+				if marshalErr != nil {
+					return fmt.Errorf("marshaling plans: %w", marshalErr)
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			} else {
+				w := writerFn()
+				for i, plan := range plans {
+					if i > 0 {
+						_, _ = fmt.Fprintln(w)
+					}
+					plan.Render(w)
+				}
+				_ = w.Flush()
+			}
+			return err
+		},
+	}
+	planCmd.Flags().BoolVar(&jsonOut, "json", false, "Emit the plans as JSON")
+	return planCmd
+}
+
+// planPlugins runs the plan subcommand of every requested plugin. A plugin
+// that cannot plan is reported in the returned error without hiding the
+// plans of the others.
+func planPlugins(ctx context.Context, plugins []*PluginPkg) ([]*pluginkit.Plan, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var plans []*pluginkit.Plan
+	var errs []error
+	requested := false
+	for _, p := range plugins {
+		if !p.Requested {
+			continue
+		}
+		requested = true
+		if !p.Installed || p.Command == nil {
+			errs = append(errs, fmt.Errorf("%s: requested plugin is not installed", p.Name))
+			continue
+		}
+		plan, err := execPlan(ctx, p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s (service %s): %w", p.Name, p.ServiceTarget, err))
+			continue
+		}
+		plans = append(plans, plan)
+	}
+	if !requested {
+		return nil, errors.New("no plugins were requested in config")
+	}
+	return plans, errors.Join(errs...)
+}
+
+// execPlan runs the plugin's plan subcommand with the same arguments a run
+// would pass it, and decodes the JSON plan from stdout. stderr is only used
+// to explain a failure.
+func execPlan(ctx context.Context, p *PluginPkg) (*pluginkit.Plan, error) {
+	ctx, cancel := context.WithTimeout(ctx, planExecTimeout)
+	defer cancel()
+
+	args := append([]string{pluginkit.PlanCommand, "--json"}, p.Command.Args[1:]...)
+	cmd := exec.CommandContext(ctx, p.Command.Path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return nil, fmt.Errorf("%s %s: %w: %s", filepath.Base(p.Command.Path), pluginkit.PlanCommand, err, detail)
+		}
+		return nil, fmt.Errorf("%s %s: %w", filepath.Base(p.Command.Path), pluginkit.PlanCommand, err)
+	}
+	plan := &pluginkit.Plan{}
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), plan); err != nil {
+		return nil, fmt.Errorf("decoding plan JSON (the plugin may predate plan mode; rebuilding it against a newer privateer-sdk will add it): %w", err)
+	}
+	return plan, nil
+}
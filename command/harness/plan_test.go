@@ -0,0 +1,80 @@
+package harness
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakePlanPlugin writes a shell script standing in for a plugin binary: it
+// checks it was asked to plan, then prints body.
+func fakePlanPlugin(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell-script plugin stand-in")
+	}
+	p := filepath.Join(t.TempDir(), "plugin")
+	script := "#!/bin/sh\n[ \"$1\" = plan ] && [ \"$2\" = --json ] || { echo \"unexpected args: $*\" >&2; exit 2; }\n" + body
+	if err := os.WriteFile(p, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPlanPlugins(t *testing.T) {
+	good := fakePlanPlugin(t, `echo '{"plugin-name":"example","service-name":"svc","test-suite":"default","catalogs":[{"catalog-id":"CCC.ObjStor","requirements":[]}]}'`)
+	broken := fakePlanPlugin(t, "echo 'bad config' >&2; exit 2")
+
+	tests := []struct {
+		name      string
+		plugins   []*PluginPkg
+		wantPlans int
+		wantErr   string
+	}{
+		{
+			name:      "plans each requested plugin",
+			plugins:   []*PluginPkg{{Name: "example", ServiceTarget: "svc", Requested: true, Installed: true, Command: exec.Command(good, "--service=svc")}},
+			wantPlans: 1,
+		},
+		{
+			name: "one failing plugin does not hide the others",
+			plugins: []*PluginPkg{
+				{Name: "broken", ServiceTarget: "other", Requested: true, Installed: true, Command: exec.Command(broken)},
+				{Name: "example", ServiceTarget: "svc", Requested: true, Installed: true, Command: exec.Command(good)},
+			},
+			wantPlans: 1,
+			wantErr:   "broken (service other): plugin plan: exit status 2: bad config",
+		},
+		{
+			name:    "not installed",
+			plugins: []*PluginPkg{{Name: "missing", Requested: true}},
+			wantErr: "missing: requested plugin is not installed",
+		},
+		{
+			name:    "nothing requested",
+			plugins: []*PluginPkg{{Name: "local", Installed: true}},
+			wantErr: "no plugins were requested in config",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plans, err := planPlugins(context.Background(), tt.plugins)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if len(plans) != tt.wantPlans {
+				t.Fatalf("got %d plans, want %d", len(plans), tt.wantPlans)
+			}
+			if len(plans) > 0 && (plans[0].ServiceName != "svc" || plans[0].Catalogs[0].CatalogId != "CCC.ObjStor") {
+				t.Errorf("unexpected plan: %+v", plans[0])
+			}
+		})
+	}
+}
@@ -27,9 +27,11 @@ import (
 // the writer and maps flags to publish.Params.
 func publishCmd(writerFn func() Writer) *cobra.Command {
 	var (
-		distDir  string
-		registry string
-		noSync   bool
+		distDir        string
+		registry       string
+		noSync         bool
+		minCoverage    float64
+		failOnOrphaned bool
 	)
 
 	publishCmd := &cobra.Command{
@@ -44,19 +46,25 @@ func publishCmd(writerFn func() Writer) *cobra.Command {
 			"Authenticate first with `pvtr login` (interactive device grant); in CI set " +
 			"PVTR_TOKEN to a GitHub-Actions OIDC token (trusted publishing). Use --registry " +
 			"to push to a different host (e.g. a local zot or GHCR) for testing — that path " +
-			"is anonymous and skips sync.",
+			"is anonymous and skips sync.\n\n" +
+			"--min-coverage and --fail-on-orphaned read the plugin's step coverage " +
+			"report (its coverage subcommand) and stop the publish before anything is pushed.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			w := writerFn()
 			defer func() { _ = w.Flush() }()
 			return publish.Publish(cmd.Context(), w, publish.Params{
-				DistDir:  distDir,
-				Registry: registry,
-				NoSync:   noSync,
+				DistDir:        distDir,
+				Registry:       registry,
+				NoSync:         noSync,
+				MinCoverage:    minCoverage,
+				FailOnOrphaned: failOnOrphaned,
 			})
 		},
 	}
 	publishCmd.Flags().StringVar(&distDir, "dist", "dist", "GoReleaser dist directory (contains artifacts.json + metadata.json)")
 	publishCmd.Flags().StringVar(&registry, "registry", "", "registry override WITH scheme for testing, e.g. http://localhost:5000 or https://ghcr.io/<owner> (anonymous, skips signing + sync)")
 	publishCmd.Flags().BoolVar(&noSync, "no-sync", false, "push-only smoke: skip signing and /sync (no signing identity needed)")
+	publishCmd.Flags().Float64Var(&minCoverage, "min-coverage", 0, "refuse to publish when any evaluated catalog has steps for less than this percentage of its requirements")
+	publishCmd.Flags().BoolVar(&failOnOrphaned, "fail-on-orphaned", false, "refuse to publish when steps are registered for ids that are not in the catalog")
 	return publishCmd
 }
@@ -0,0 +1,269 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/goccy/go-yaml"
+
+	"github.com/privateerproj/privateer-sdk/config"
+	"github.com/privateerproj/privateer-sdk/utils"
+)
+
+// SummaryFileName is the file, in the write directory, that holds the run
+// summary written at the end of harness.Run.
+const SummaryFileName = "summary.json"
+
+// RunSummarySchema identifies the layout of summary.json so consumers can
+// detect a future incompatible change.
+const RunSummarySchema = "privateer.run-summary/v1"
+
+// RunSummary is the consolidated, machine-readable outcome of a harness run.
+type RunSummary struct {
+	Schema   string           `json:"schema"`
+	ExitCode int              `json:"exit-code"`
+	Services []ServiceSummary `json:"services"`
+}
+
+// ServiceSummary is one requested service's row in the run summary. Counts are
+// per control evaluation; NeedsReview covers every result that is neither
+// Passed, Failed nor NotRun, matching how a suite tallies its warnings. When
+// the results cannot be read back by design, e.g. because they were streamed
+// to stdout, the counts are left at zero and Note says why.
+type ServiceSummary struct {
+	Service        string   `json:"service"`
+	Plugin         string   `json:"plugin"`
+	Version        string   `json:"version,omitempty"`
+	Catalogs       []string `json:"catalogs"`
+	Passed         int      `json:"passed"`
+	Failed         int      `json:"failed"`
+	NeedsReview    int      `json:"needs-review"`
+	CorruptedState bool     `json:"corrupted-state"`
+	Error          string   `json:"error,omitempty"`
+	Note           string   `json:"note,omitempty"`
+	DurationNs     int64    `json:"duration-ns"`
+	ExitCode       int      `json:"exit-code"`
+	ResultsFile    string   `json:"results-file,omitempty"`
+}
+
+// writtenResults is the subset of a plugin's yaml/json results the summary
+// reads. It decodes the orchestrator envelope; the gemara output format is a
+// bare list of EvaluationLogs and is read with writtenLog instead.
+type writtenResults struct {
+	PluginVersion    string         `yaml:"plugin-version"`
+	EvaluationSuites []writtenSuite `yaml:"evaluation-suites"`
+}
+
+type writtenSuite struct {
+	CatalogId      string     `yaml:"catalog-id"`
+	CorruptedState bool       `yaml:"corrupted-state"`
+	EvaluationLog  writtenLog `yaml:"control-evaluations"`
+}
+
+// writtenLog decodes only the results of an EvaluationLog: its steps are
+// serialized by function name and cannot be read back.
+type writtenLog struct {
+	Metadata struct {
+		Description string `yaml:"description"`
+		Author      struct {
+			Version string `yaml:"version"`
+		} `yaml:"author"`
+	} `yaml:"metadata"`
+	Evaluations []struct {
+		Result         gemara.Result       `yaml:"result"`
+		Control        gemara.EntryMapping `yaml:"control"`
+		AssessmentLogs []struct {
+			Requirement gemara.EntryMapping `yaml:"requirement"`
+			Result      gemara.Result       `yaml:"result"`
+			Message     string              `yaml:"message"`
+		} `yaml:"assessment-logs"`
+	} `yaml:"evaluations"`
+}
+
+// corruptedStateMarker is how a standalone gemara log reports corrupted state;
+// see EvaluationOrchestrator.stampEvaluationLog.
+const corruptedStateMarker = "corrupted-state: true"
+
+// buildRunSummary combines each requested plugin's run state with the results
+// it wrote under writeDir. A service whose results cannot be found or read is
+// still summarized, with the problem recorded as its error.
+func buildRunSummary(plugins []*PluginPkg, exitCode int, writeDir string) RunSummary {
+	summary := RunSummary{Schema: RunSummarySchema, ExitCode: exitCode, Services: []ServiceSummary{}}
+	for _, p := range plugins {
+		if !p.Requested {
+			continue
+		}
+		s := ServiceSummary{
+			Service:    p.ServiceTarget,
+			Plugin:     p.Name,
+			Version:    p.Version,
+			Catalogs:   []string{},
+			DurationNs: p.Duration.Nanoseconds(),
+			ExitCode:   p.ExitCode,
+		}
+		switch {
+		case p.Error != nil:
+			s.Error = p.Error.Error()
+		case p.Duration == 0:
+			s.Error = "plugin did not run"
+		}
+		if p.Duration > 0 {
+			if note := unreadableResults(); note != "" {
+				s.Note = note
+			} else if err := s.readResults(writeDir); err != nil && s.Error == "" {
+				s.Error = err.Error()
+			}
+		}
+		summary.Services = append(summary.Services, s)
+	}
+	return summary
+}
+
+// readableFormats are the outputs readResults can count.
+var readableFormats = []string{"yaml", "json", "gemara"}
+
+// unreadableResults explains why no service's results can be read back with
+// the configured output, or is empty when they can.
+func unreadableResults() string {
+	if config.StreamsResults() {
+		return "results streamed to stdout"
+	}
+	formats := config.OutputFormats()
+	for _, format := range formats {
+		if slices.Contains(readableFormats, format) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("results written as %s; counts need yaml, json or gemara", strings.Join(formats, ", "))
+}
+
+// readResults fills the counts from the service's results file. Only the yaml,
+// json and gemara outputs can be read back; sarif carries no per-control results.
+func (s *ServiceSummary) readResults(writeDir string) error {
+	if writeDir == "" {
+		return fmt.Errorf("no write-directory is set, so the results could not be located")
+	}
+	for _, ext := range []string{".yaml", ".json", ".gemara.yaml"} {
+		resultsFile := filepath.Join(writeDir, s.Service, s.Service+ext)
+		data, err := os.ReadFile(resultsFile)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading results: %w", err)
+		}
+		s.ResultsFile = resultsFile
+		if err := s.countResults(data); err != nil {
+			return fmt.Errorf("parsing results %s: %w", resultsFile, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no yaml, json or gemara results found in %s", filepath.Join(writeDir, s.Service))
+}
+
+// countResults accepts either the orchestrator envelope or a gemara list.
+func (s *ServiceSummary) countResults(data []byte) error {
+	results, err := parseResults(data)
+	if err != nil {
+		return err
+	}
+	if s.Version == "" {
+		s.Version = results.PluginVersion
+	}
+	for _, suite := range results.EvaluationSuites {
+		if suite.CatalogId != "" {
+			s.Catalogs = append(s.Catalogs, suite.CatalogId)
+		}
+		s.CorruptedState = s.CorruptedState || suite.CorruptedState
+		s.count(suite.EvaluationLog)
+	}
+	return nil
+}
+
+// parseResults decodes a plugin's results into the envelope shape. A gemara
+// list (output: gemara) becomes one suite per log, with the catalog taken from
+// its evaluations and the plugin version from its author. JSON is valid YAML,
+// so one decoder serves both extensions.
+func parseResults(data []byte) (writtenResults, error) {
+	var results writtenResults
+	if !strings.HasPrefix(strings.TrimSpace(string(data)), "-") {
+		err := yaml.Unmarshal(data, &results)
+		return results, err
+	}
+	var logs []writtenLog
+	if err := yaml.Unmarshal(data, &logs); err != nil {
+		return results, err
+	}
+	for _, l := range logs {
+		if results.PluginVersion == "" {
+			results.PluginVersion = l.Metadata.Author.Version
+		}
+		suite := writtenSuite{
+			CorruptedState: strings.Contains(l.Metadata.Description, corruptedStateMarker),
+			EvaluationLog:  l,
+		}
+		if len(l.Evaluations) > 0 {
+			suite.CatalogId = l.Evaluations[0].Control.ReferenceId
+		}
+		results.EvaluationSuites = append(results.EvaluationSuites, suite)
+	}
+	return results, nil
+}
+
+func (s *ServiceSummary) count(l writtenLog) {
+	for _, evaluation := range l.Evaluations {
+		switch evaluation.Result {
+		case gemara.Passed:
+			s.Passed++
+		case gemara.Failed:
+			s.Failed++
+		case gemara.NotRun:
+		default:
+			s.NeedsReview++
+		}
+	}
+}
+
+// renderRunSummary prints one row per service, in the order the plugins ran.
+func renderRunSummary(w Writer, summary RunSummary) {
+	_, _ = fmt.Fprintf(w, "Run summary: %d service(s); exit code %d\n", len(summary.Services), summary.ExitCode)
+	_, _ = fmt.Fprintln(w, "SERVICE\tPLUGIN\tVERSION\tCATALOGS\tPASSED\tFAILED\tNEEDS REVIEW\tCORRUPTED\tDURATION\tERROR\tNOTE")
+	for _, s := range summary.Services {
+		version := s.Version
+		if version == "" {
+			version = "latest"
+		}
+		corrupted := "no"
+		if s.CorruptedState {
+			corrupted = "YES"
+		}
+		counts := []string{fmt.Sprint(s.Passed), fmt.Sprint(s.Failed), fmt.Sprint(s.NeedsReview)}
+		if s.Note != "" {
+			counts = []string{"-", "-", "-"} // not known, rather than zero
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%v\t%s\t%s\n",
+			s.Service, s.Plugin, version, strings.Join(s.Catalogs, ","),
+			strings.Join(counts, "\t"), corrupted, formatNs(s.DurationNs), s.Error, s.Note)
+	}
+}
+
+// writeRunSummary writes summary.json into writeDir, creating it if needed.
+func writeRunSummary(summary RunSummary, writeDir string) (string, error) {
+	if err := os.MkdirAll(writeDir, utils.DirPermissions); err != nil {
+		return "", fmt.Errorf("creating write directory: %w", err)
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding run summary: %w", err)
+	}
+	summaryPath := filepath.Join(writeDir, SummaryFileName)
+	if err := os.WriteFile(summaryPath, data, 0o640); err != nil {
+		return "", fmt.Errorf("writing run summary: %w", err)
+	}
+	return summaryPath, nil
+}
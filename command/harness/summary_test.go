@@ -0,0 +1,209 @@
+package harness
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const envelopeResults = `service-name: svc-a
+plugin-name: example
+plugin-version: 1.2.3
+evaluation-suites:
+  - name: svc-a_CCC.ObjStor
+    catalog-id: CCC.ObjStor
+    corrupted-state: true
+    control-evaluations:
+      evaluations:
+        - name: CCC.ObjStor.C01
+          result: Passed
+          control:
+            reference-id: CCC.ObjStor
+            entry-id: CCC.ObjStor.C01
+        - name: CCC.ObjStor.C02
+          result: Failed
+        - name: CCC.ObjStor.C03
+          result: Needs Review
+        - name: CCC.ObjStor.C04
+          result: Not Run
+`
+
+const gemaraResults = `- metadata:
+    description: "Privateer evaluation; corrupted-state: true (an invasive change failed to revert)"
+    author:
+      version: 2.0.0
+  evaluations:
+    - result: Passed
+      control:
+        reference-id: CCC.Core
+        entry-id: CCC.Core.C01
+    - result: Unknown
+`
+
+func writeServiceResults(t *testing.T, writeDir, service, ext, content string) {
+	t.Helper()
+	dir := filepath.Join(writeDir, service)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, service+ext), []byte(content), 0o640); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildRunSummary(t *testing.T) {
+	writeDir := t.TempDir()
+	writeServiceResults(t, writeDir, "svc-a", ".yaml", envelopeResults)
+	writeServiceResults(t, writeDir, "svc-b", ".gemara.yaml", gemaraResults)
+
+	plugins := []*PluginPkg{
+		{Name: "example", ServiceTarget: "svc-a", Requested: true, Duration: time.Second, ExitCode: TestFail},
+		{Name: "example", Version: "9.9.9", ServiceTarget: "svc-b", Requested: true, Duration: time.Second},
+		{Name: "example", ServiceTarget: "svc-c", Requested: true, Duration: time.Second, ExitCode: InternalError, Error: errors.New("plugin svc-c: boom")},
+		{Name: "example", ServiceTarget: "svc-d", Requested: true},
+		{Name: "unrequested", ServiceTarget: "svc-e"},
+	}
+	summary := buildRunSummary(plugins, TestFail, writeDir)
+
+	if summary.Schema != RunSummarySchema || summary.ExitCode != TestFail {
+		t.Errorf("unexpected header: %+v", summary)
+	}
+	if len(summary.Services) != 4 {
+		t.Fatalf("expected the 4 requested services, got %d", len(summary.Services))
+	}
+
+	a := summary.Services[0]
+	if a.Version != "1.2.3" || strings.Join(a.Catalogs, ",") != "CCC.ObjStor" {
+		t.Errorf("svc-a: expected version and catalog from the results, got %q %v", a.Version, a.Catalogs)
+	}
+	if a.Passed != 1 || a.Failed != 1 || a.NeedsReview != 1 || !a.CorruptedState {
+		t.Errorf("svc-a: unexpected counts %+v", a)
+	}
+	if a.Error != "" || a.ExitCode != TestFail || a.DurationNs != time.Second.Nanoseconds() {
+		t.Errorf("svc-a: unexpected run state %+v", a)
+	}
+	if a.ResultsFile != filepath.Join(writeDir, "svc-a", "svc-a.yaml") {
+		t.Errorf("svc-a: unexpected results file %q", a.ResultsFile)
+	}
+
+	b := summary.Services[1]
+	if b.Version != "9.9.9" {
+		t.Errorf("svc-b: the requested version should win, got %q", b.Version)
+	}
+	if b.Passed != 1 || b.NeedsReview != 1 || !b.CorruptedState || strings.Join(b.Catalogs, ",") != "CCC.Core" {
+		t.Errorf("svc-b: unexpected gemara-format summary %+v", b)
+	}
+
+	if c := summary.Services[2]; c.Error != "plugin svc-c: boom" {
+		t.Errorf("svc-c: expected the plugin error to take precedence, got %q", c.Error)
+	}
+	if d := summary.Services[3]; d.Error != "plugin did not run" || d.ResultsFile != "" {
+		t.Errorf("svc-d: expected a not-run row, got %+v", d)
+	}
+}
+
+func TestReadResults_Errors(t *testing.T) {
+	writeDir := t.TempDir()
+	writeServiceResults(t, writeDir, "bad", ".json", "{not valid")
+
+	for _, tc := range []struct {
+		name, writeDir, service, want string
+	}{
+		{"no write directory", "", "svc", "no write-directory is set"},
+		{"missing results", writeDir, "absent", "no yaml, json or gemara results found"},
+		{"malformed results", writeDir, "bad", "parsing results"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &ServiceSummary{Service: tc.service}
+			err := s.readResults(tc.writeDir)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestBuildRunSummary_UnreadableResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		wantNote string
+	}{
+		{name: "results on disk", settings: map[string]any{"output": "yaml,sarif"}},
+		{name: "streamed to stdout", settings: map[string]any{"output-destination": "stdout"}, wantNote: "results streamed to stdout"},
+		{name: "no readable format", settings: map[string]any{"output": "junit,oscal"}, wantNote: "results written as junit, oscal; counts need yaml, json or gemara"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			for key, value := range tt.settings {
+				viper.Set(key, value)
+			}
+			writeDir := t.TempDir()
+			writeServiceResults(t, writeDir, "svc-a", ".yaml", envelopeResults)
+			plugins := []*PluginPkg{{Name: "example", ServiceTarget: "svc-a", Requested: true, Duration: time.Second}}
+
+			s := buildRunSummary(plugins, TestPass, writeDir).Services[0]
+
+			if s.Error != "" || s.Note != tt.wantNote {
+				t.Errorf("error = %q, note = %q; want no error and note %q", s.Error, s.Note, tt.wantNote)
+			}
+			if tt.wantNote != "" && (s.Passed != 0 || s.ResultsFile != "") {
+				t.Errorf("expected no counts when the results cannot be read back, got %+v", s)
+			}
+			if tt.wantNote == "" && s.Passed != 1 {
+				t.Errorf("expected the results on disk to be counted, got %+v", s)
+			}
+		})
+	}
+}
+
+func TestRenderRunSummary(t *testing.T) {
+	w := &benchBufWriter{}
+	renderRunSummary(w, RunSummary{ExitCode: TestFail, Services: []ServiceSummary{
+		{Service: "svc-a", Plugin: "example", Catalogs: []string{"CCC.ObjStor", "CCC.Core"}, Passed: 3, Failed: 1, CorruptedState: true, DurationNs: int64(1500 * time.Millisecond)},
+		{Service: "svc-b", Plugin: "example", DurationNs: int64(time.Second), Note: "results streamed to stdout"},
+	}})
+	out := w.String()
+	for _, want := range []string{
+		"Run summary: 2 service(s); exit code 1",
+		"SERVICE\tPLUGIN\tVERSION\tCATALOGS\tPASSED\tFAILED\tNEEDS REVIEW\tCORRUPTED\tDURATION\tERROR\tNOTE",
+		"svc-a\texample\tlatest\tCCC.ObjStor,CCC.Core\t3\t1\t0\tYES\t1.5s\t",
+		"svc-b\texample\tlatest\t\t-\t-\t-\tno\t1s\t\tresults streamed to stdout",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteRunSummary(t *testing.T) {
+	writeDir := filepath.Join(t.TempDir(), "nested")
+	summary := RunSummary{Schema: RunSummarySchema, Services: []ServiceSummary{{Service: "svc-a", Catalogs: []string{}}}}
+
+	summaryPath, err := writeRunSummary(summary, writeDir)
+	if err != nil {
+		t.Fatalf("writeRunSummary: %v", err)
+	}
+	if summaryPath != filepath.Join(writeDir, SummaryFileName) {
+		t.Errorf("unexpected path %q", summaryPath)
+	}
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got RunSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("summary.json is not valid JSON: %v", err)
+	}
+	if got.Schema != RunSummarySchema || len(got.Services) != 1 || got.Services[0].Service != "svc-a" {
+		t.Errorf("round-trip mismatch: %+v", got)
+	}
+}
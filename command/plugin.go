@@ -33,8 +33,16 @@ func NewPluginCommands(pluginName, buildVersion, buildGitCommitHash, buildTime s
 
 	runCmd.AddCommand(debugCommand())
 
+	runCmd.AddCommand(planCommand())
+
 	runCmd.AddCommand(publishManifestCommand())
 
+	runCmd.AddCommand(describeCommand(buildVersion))
+
+	runCmd.AddCommand(coverageCommand())
+
+	runCmd.AddCommand(revertCommand())
+
 	runCmd.AddCommand(
 		versionCommand(buildVersion, buildGitCommitHash, buildTime))
 
@@ -76,6 +84,41 @@ func debugCommand() *cobra.Command {
 	}
 }
 
+// planCommand prints what a run of the configured service would execute:
+// catalogs matched, requirements selected, the steps bound to each, and the
+// invasive changes that could be applied. No loader, step or change runs, and
+// nothing is written. `pvtr plan` execs it with --json.
+func planCommand() *cobra.Command {
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   pluginkit.PlanCommand,
+		Short: "Show what a run would execute, without loading data or running steps",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if ActiveEvaluationOrchestrator == nil {
+				return fmt.Errorf("no active evaluation orchestrator")
+			}
+			// a plan must not truncate the service's log from the last real run
+			viper.Set("write", false)
+			plan, err := ActiveEvaluationOrchestrator.Plan()
+			if err != nil {
+				return err
+			}
+			if !jsonOut {
+				plan.Render(cmd.OutOrStdout())
+				return nil
+			}
+			b, err := json.MarshalIndent(plan, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding plan: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(append(b, '\n'))
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Emit the plan as JSON")
+	return cmd
+}
+
 // publishManifestCommand emits the plugin's grc.store publish manifest
 // (coordinate + evaluated catalogs) as JSON on stdout. It reads from the active
 // orchestrator, which the plugin author populated at construction time — so it
@@ -106,6 +149,100 @@ func publishManifestCommand() *cobra.Command {
 	}
 }
 
+// describeCommand emits the plugin's capability metadata as JSON: SDK and
+// plugin versions, required vars, reference catalogs with every requirement
+// and the steps bound to it, invasive changes, and output formats. Like
+// publish-manifest it needs no config and runs nothing.
+func describeCommand(buildVersion string) *cobra.Command {
+	return &cobra.Command{
+		Use:   pluginkit.DescribeCommand,
+		Short: "Emit the plugin's capabilities (catalogs, requirements, steps, changes) as JSON.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if ActiveEvaluationOrchestrator == nil {
+				return fmt.Errorf("no active evaluation orchestrator")
+			}
+			description := ActiveEvaluationOrchestrator.Describe()
+			if description.PluginVersion == "" {
+				description.PluginVersion = buildVersion
+			}
+			b, err := json.MarshalIndent(description, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding plugin description: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(append(b, '\n'))
+			return err
+		},
+	}
+}
+
+// coverageCommand reports, per catalog, the requirements with and without
+// registered steps and any step ids the catalog does not define. With
+// --min-coverage or --fail-on-orphaned, the flags pvtr publish gates on too,
+// it exits non-zero when the check fails, so it can gate CI. It needs no
+// config and runs nothing.
+func coverageCommand() *cobra.Command {
+	var (
+		jsonOut        bool
+		minCoverage    float64
+		failOnOrphaned bool
+	)
+	cmd := &cobra.Command{
+		Use:   pluginkit.CoverageCommand,
+		Short: "Report which catalog requirements have registered steps.",
+		// a failed check shouldn't reprint usage text
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if ActiveEvaluationOrchestrator == nil {
+				return fmt.Errorf("no active evaluation orchestrator")
+			}
+			report := ActiveEvaluationOrchestrator.Coverage()
+			if jsonOut {
+				b, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("encoding coverage report: %w", err)
+				}
+				if _, err := cmd.OutOrStdout().Write(append(b, '\n')); err != nil {
+					return err
+				}
+			} else {
+				report.Render(cmd.OutOrStdout())
+			}
+			return report.Check(minCoverage, failOnOrphaned)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Emit the coverage report as JSON")
+	cmd.Flags().Float64Var(&minCoverage, "min-coverage", 0, "Fail when any catalog has steps for less than this percentage of its requirements")
+	cmd.Flags().BoolVar(&failOnOrphaned, "fail-on-orphaned", false, "Fail when steps are registered for ids that are not in the catalog")
+	return cmd
+}
+
+// revertCommand reverts the invasive changes a run left applied, newest
+// first, as recorded in the service's change journal. It is the cleanup for a
+// run whose process died before it could revert its own changes.
+func revertCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   pluginkit.RevertCommand,
+		Short: "Revert invasive changes left applied by a run that did not finish",
+		// a failed revert shouldn't reprint usage text
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if ActiveEvaluationOrchestrator == nil {
+				return fmt.Errorf("no active evaluation orchestrator")
+			}
+			// keep the log of the run being cleaned up
+			viper.Set("write", false)
+			reverted, err := ActiveEvaluationOrchestrator.RevertJournal()
+			for _, entry := range reverted {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "reverted %s on %s (applied %s)\n", entry.Change, entry.TargetName, entry.AppliedAt)
+			}
+			if err == nil && len(reverted) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "no journaled changes to revert")
+			}
+			return err
+		},
+	}
+}
+
 func versionCommand(
 	buildVersion, buildGitCommitHash, buildTime string) *cobra.Command {
 	return &cobra.Command{
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/privateerproj/privateer-sdk/pluginkit"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -93,3 +94,65 @@ func TestVersionCommand(t *testing.T) {
 		t.Error("Expected cmd.Run to be set")
 	}
 }
+
+func TestDescribeCommand(t *testing.T) {
+	cmd := describeCommand(buildVersion)
+	if cmd.Use != pluginkit.DescribeCommand {
+		t.Errorf("Expected cmd.Use to be %q, but got %s", pluginkit.DescribeCommand, cmd.Use)
+	}
+
+	// The description itself is covered by pluginkit's TestEvaluationOrchestrator_Describe;
+	// this asserts the wiring and the build-version fallback.
+	ActiveEvaluationOrchestrator = &pluginkit.EvaluationOrchestrator{PluginName: pluginName}
+	t.Cleanup(func() { ActiveEvaluationOrchestrator = nil })
+	var out strings.Builder
+	cmd.SetOut(&out)
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("describe failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `"plugin-version": "1.0.0"`) {
+		t.Errorf("expected the build version as the plugin version, got %s", out.String())
+	}
+}
+
+func TestCoverageCommand(t *testing.T) {
+	cmd := coverageCommand()
+	if cmd.Use != pluginkit.CoverageCommand {
+		t.Errorf("Expected cmd.Use to be %q, but got %s", pluginkit.CoverageCommand, cmd.Use)
+	}
+	ActiveEvaluationOrchestrator = &pluginkit.EvaluationOrchestrator{}
+	t.Cleanup(func() { ActiveEvaluationOrchestrator = nil })
+	var out strings.Builder
+	cmd.SetOut(&out)
+	if err := cmd.Flags().Set("json", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("coverage failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `"catalogs": []`) {
+		t.Errorf("unexpected coverage JSON: %s", out.String())
+	}
+}
+
+func TestRevertCommand(t *testing.T) {
+	cmd := revertCommand()
+	if cmd.Use != pluginkit.RevertCommand {
+		t.Errorf("Expected cmd.Use to be %q, but got %s", pluginkit.RevertCommand, cmd.Use)
+	}
+	viper.Set("service", "test-service")
+	viper.Set("services.test-service.policy.catalogs", []string{"CCC.ObjStor"})
+	viper.Set("services.test-service.policy.applicability", []string{"tlp-green"})
+	viper.Set("change-journal-directory", t.TempDir())
+	t.Cleanup(viper.Reset)
+	ActiveEvaluationOrchestrator = &pluginkit.EvaluationOrchestrator{PluginName: pluginName}
+	t.Cleanup(func() { ActiveEvaluationOrchestrator = nil })
+	var out strings.Builder
+	cmd.SetOut(&out)
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("revert failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "no journaled changes to revert") {
+		t.Errorf("unexpected revert output: %s", out.String())
+	}
+}
@@ -1,14 +1,21 @@
 package command
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"sync"
+	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
 	hcplugin "github.com/hashicorp/go-plugin"
 	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/privateerproj/privateer-sdk/config"
 	"github.com/privateerproj/privateer-sdk/shared"
 )
 
@@ -84,44 +91,143 @@ func Run(logger hclog.Logger, getPlugins func() []*PluginPkg) (exitCode int) {
 		return BadUsage
 	}
 
-	var runCount int
-	for _, pluginPkg := range toRun {
-		serviceName := pluginPkg.ServiceTarget
-		runCount++
-		client := newClient(pluginPkg.Command, logger)
-		var rpcClient hcplugin.ClientProtocol
-		rpcClient, err := client.Client()
-		if err != nil {
-			logger.Error(fmt.Sprintf("internal error while initializing %s RPC client: %s", serviceName, err))
-			pluginPkg.closeClient(serviceName, client, logger)
-			return InternalError
+	if workers := config.ParallelPlugins(); workers > 1 && len(toRun) > 1 {
+		runners := make([]pluginRunner, len(toRun))
+		for i, pluginPkg := range toRun {
+			runners[i] = pluginPkg
 		}
-		var rawPlugin interface{}
-		rawPlugin, err = rpcClient.Dispense(shared.PluginName)
+		return runConcurrently(logger, runners, workers, os.Stdout, os.Stderr)
+	}
+
+	for i, pluginPkg := range toRun {
+		pluginExitCode, err := pluginPkg.start(i+1, logger, os.Stdout, os.Stderr)
 		if err != nil {
-			logger.Error(fmt.Sprintf("internal error while dispensing RPC client: %s", err.Error()))
-			pluginPkg.closeClient(serviceName, client, logger)
 			return InternalError
 		}
-		plugin := rawPlugin.(shared.Pluginer)
-		logger.Trace(fmt.Sprintf("Starting Plugin %v: %s", runCount, pluginPkg.Name))
-		pluginExitCode, response := plugin.Start()
-		if response != nil {
-			pluginPkg.Error = fmt.Errorf("plugin %s: %v", serviceName, response)
-		}
-		pluginPkg.Successful = pluginExitCode == TestPass
-		if !pluginPkg.Successful {
+		exitCode = mergeExitCode(exitCode, pluginExitCode)
+	}
+	return exitCode
+}
+
+// pluginRunner is a plugin runConcurrently can start. PluginPkg runs one
+// over go-plugin; tests substitute a fake.
+type pluginRunner interface {
+	serviceTarget() string
+	start(runCount int, logger hclog.Logger, stdout, stderr io.Writer) (exitCode int, err error)
+}
+
+// runConcurrently executes up to workers plugins at once. Each plugin's output
+// and host-side log lines are buffered and flushed to stdout and stderr as one
+// block when it exits, so concurrent plugins never interleave mid-line. As in
+// the serial loop, a plugin that cannot be started over RPC stops any further
+// plugins from starting; plugins already running are allowed to finish.
+func runConcurrently(logger hclog.Logger, toRun []pluginRunner, workers int, stdout, stderr io.Writer) (exitCode int) {
+	logger.Trace(fmt.Sprintf("Running %d plugins with up to %d at once", len(toRun), workers))
+
+	var mu sync.Mutex // guards exitCode and flushes to the terminal
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(workers)
+	for i, runner := range toRun {
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var pluginStdout, pluginStderr syncBuffer
+			pluginExitCode, err := runner.start(i+1, pluginLogger(logger, runner.serviceTarget(), &pluginStderr), &pluginStdout, &pluginStderr)
+
+			mu.Lock()
+			defer mu.Unlock()
+			_, _ = pluginStdout.WriteTo(stdout)
+			_, _ = pluginStderr.WriteTo(stderr)
+			if err != nil {
+				exitCode = mergeExitCode(exitCode, InternalError)
+				return err
+			}
 			exitCode = mergeExitCode(exitCode, pluginExitCode)
-		}
-		pluginPkg.closeClient(serviceName, client, logger)
+			return nil
+		})
 	}
+	_ = g.Wait()
 	return exitCode
 }
 
+// pluginLogger derives the logger of one concurrently run plugin from the
+// host logger, so it keeps the host's level, format and other settings but
+// writes to the plugin's buffer.
+func pluginLogger(logger hclog.Logger, name string, output io.Writer) hclog.Logger {
+	named := logger.ResetNamed(name)
+	if resettable, ok := named.(hclog.OutputResettable); ok && resettable.ResetOutput(&hclog.LoggerOptions{Output: output}) == nil {
+		return named
+	}
+	// a logger hclog did not build cannot be redirected; keep its level at least
+	return hclog.New(&hclog.LoggerOptions{
+		Name:   name,
+		Level:  logger.GetLevel(),
+		Output: output,
+	})
+}
+
+func (p *PluginPkg) serviceTarget() string {
+	return p.ServiceTarget
+}
+
+// start runs the plugin to completion over go-plugin and returns its exit
+// code. A non-nil error means the RPC client could not be set up; the plugin
+// never ran and the caller should treat the run as an InternalError.
+func (p *PluginPkg) start(runCount int, logger hclog.Logger, stdout, stderr io.Writer) (exitCode int, err error) {
+	serviceName := p.ServiceTarget
+	started := time.Now()
+	defer func() {
+		p.ExitCode = exitCode
+		p.Duration = time.Since(started)
+	}()
+	client := newClient(p.Command, logger, stdout, stderr)
+	rpcClient, err := client.Client()
+	if err != nil {
+		logger.Error(fmt.Sprintf("internal error while initializing %s RPC client: %s", serviceName, err))
+		p.closeClient(serviceName, client, logger)
+		return InternalError, err
+	}
+	rawPlugin, err := rpcClient.Dispense(shared.PluginName)
+	if err != nil {
+		logger.Error(fmt.Sprintf("internal error while dispensing RPC client: %s", err.Error()))
+		p.closeClient(serviceName, client, logger)
+		return InternalError, err
+	}
+	plugin := rawPlugin.(shared.Pluginer)
+	logger.Trace(fmt.Sprintf("Starting Plugin %v: %s", runCount, p.Name))
+	pluginExitCode, response := plugin.Start()
+	if response != nil {
+		p.Error = fmt.Errorf("plugin %s: %v", serviceName, response)
+	}
+	p.Successful = pluginExitCode == TestPass
+	p.closeClient(serviceName, client, logger)
+	return pluginExitCode, nil
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes go-plugin and
+// hclog make while a plugin runs.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) WriteTo(w io.Writer) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.WriteTo(w)
+}
+
 // newClient handles the lifecycle of a plugin application.
 // Plugin hosts should use one Client for each plugin executable
 // (this is different from the client that manages gRPC).
-func newClient(cmd *exec.Cmd, logger hclog.Logger) *hcplugin.Client {
+func newClient(cmd *exec.Cmd, logger hclog.Logger, stdout, stderr io.Writer) *hcplugin.Client {
 	var pluginMap = map[string]hcplugin.Plugin{
 		shared.PluginName: &shared.Plugin{},
 	}
@@ -131,7 +237,7 @@ func newClient(cmd *exec.Cmd, logger hclog.Logger) *hcplugin.Client {
 		Plugins:         pluginMap,
 		Cmd:             cmd,
 		Logger:          logger,
-		SyncStdout:      os.Stdout,
-		SyncStderr:      os.Stderr,
+		SyncStdout:      stdout,
+		SyncStderr:      stderr,
 	})
 }
@@ -1,6 +1,17 @@
 package command
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
 
 func TestMergeExitCode(t *testing.T) {
 	tests := []struct {
@@ -121,3 +132,134 @@ func TestPlanRun(t *testing.T) {
 		})
 	}
 }
+
+// fakeRunner stands in for a plugin run over go-plugin: it writes a few
+// lines to its stdout, pausing between them so concurrent runs would
+// interleave if their output were not grouped, and logs through the logger
+// it was given.
+type fakeRunner struct {
+	name     string
+	exitCode int
+	err      error // an RPC setup failure
+
+	mu      *sync.Mutex
+	started *[]string
+}
+
+func (f *fakeRunner) serviceTarget() string { return f.name }
+
+func (f *fakeRunner) start(_ int, logger hclog.Logger, stdout, _ io.Writer) (int, error) {
+	f.mu.Lock()
+	*f.started = append(*f.started, f.name)
+	f.mu.Unlock()
+	if f.err != nil {
+		logger.Error("could not start plugin")
+		return InternalError, f.err
+	}
+	for i := range 3 {
+		_, _ = fmt.Fprintf(stdout, "%s line %d\n", f.name, i)
+		time.Sleep(time.Millisecond)
+	}
+	logger.Info("plugin finished")
+	return f.exitCode, nil
+}
+
+func TestRunConcurrently(t *testing.T) {
+	tests := []struct {
+		name        string
+		workers     int
+		runners     []fakeRunner
+		wantExit    int
+		wantStarted []string // in order; nil to only check the count
+		wantCount   int
+	}{
+		{
+			name:      "every plugin passes",
+			workers:   3,
+			runners:   []fakeRunner{{name: "a"}, {name: "b"}, {name: "c"}},
+			wantExit:  TestPass,
+			wantCount: 3,
+		},
+		{
+			name:      "the most severe exit code wins",
+			workers:   2,
+			runners:   []fakeRunner{{name: "a", exitCode: TestFail}, {name: "b", exitCode: BadUsage}, {name: "c"}},
+			wantExit:  BadUsage,
+			wantCount: 3,
+		},
+		{
+			name:        "an RPC failure stops further plugins from starting",
+			workers:     1,
+			runners:     []fakeRunner{{name: "a"}, {name: "b", err: errors.New("handshake failed")}, {name: "c"}},
+			wantExit:    InternalError,
+			wantStarted: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var started []string
+			runners := make([]pluginRunner, len(tt.runners))
+			for i := range tt.runners {
+				tt.runners[i].mu, tt.runners[i].started = &mu, &started
+				runners[i] = &tt.runners[i]
+			}
+			var stdout, stderr strings.Builder
+			logger := hclog.New(&hclog.LoggerOptions{Level: hclog.Info, JSONFormat: true, Output: io.Discard})
+
+			exitCode := runConcurrently(logger, runners, tt.workers, &stdout, &stderr)
+
+			if exitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", exitCode, tt.wantExit)
+			}
+			if tt.wantStarted != nil && strings.Join(started, ",") != strings.Join(tt.wantStarted, ",") {
+				t.Errorf("started %v, want %v", started, tt.wantStarted)
+			}
+			if tt.wantStarted == nil && len(started) != tt.wantCount {
+				t.Errorf("started %v, want %d plugins", started, tt.wantCount)
+			}
+
+			// each plugin's stdout is flushed as one uninterrupted block
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+			for i := 0; i+2 < len(lines); i += 3 {
+				name := strings.Fields(lines[i])[0]
+				for j := range 3 {
+					if lines[i+j] != fmt.Sprintf("%s line %d", name, j) {
+						t.Fatalf("plugin output is interleaved:\n%s", stdout.String())
+					}
+				}
+			}
+
+			// the plugin loggers keep the host logger's JSON format and name each plugin
+			for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("expected JSON log lines like the host logger's, got %q", line)
+				}
+				if entry["@module"] == "" || entry["@module"] == nil {
+					t.Errorf("expected the log line to name its plugin, got %q", line)
+				}
+			}
+		})
+	}
+}
+
+func TestPluginLogger(t *testing.T) {
+	var hostOut, pluginOut strings.Builder
+	host := hclog.New(&hclog.LoggerOptions{Level: hclog.Warn, JSONFormat: true, Output: &hostOut})
+
+	logger := pluginLogger(host, "acme/scanner", &pluginOut)
+	logger.Info("below the host level")
+	logger.Warn("from the plugin")
+	host.Warn("from the host")
+
+	if logger.GetLevel() != hclog.Warn {
+		t.Errorf("level = %s, want the host's %s", logger.GetLevel(), hclog.Warn)
+	}
+	if strings.Contains(pluginOut.String(), "below the host level") || !strings.Contains(pluginOut.String(), `"@module":"acme/scanner"`) {
+		t.Errorf("expected one JSON line named for the plugin, got %q", pluginOut.String())
+	}
+	if strings.Contains(hostOut.String(), "from the plugin") || !strings.Contains(hostOut.String(), "from the host") {
+		t.Errorf("expected the host logger to keep its own output, got %q", hostOut.String())
+	}
+}
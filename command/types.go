@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
 	hcplugin "github.com/hashicorp/go-plugin"
 	"github.com/spf13/viper"
 
+	"github.com/privateerproj/privateer-sdk/config"
 	"github.com/privateerproj/privateer-sdk/internal/manifest"
 )
 
@@ -42,6 +45,9 @@ type PluginPkg struct {
 	Requested   bool
 	Successful  bool
 	Error       error
+
+	ExitCode int           // set once the plugin has run
+	Duration time.Duration // wall clock of the plugin run, including startup; zero if it never started
 }
 
 // getBinary resolves the on-disk path of the plugin binary from the manifest.
@@ -74,6 +80,28 @@ func (p *PluginPkg) queueCmd() {
 		fmt.Sprintf("--loglevel=%s", viper.GetString("loglevel")),
 		fmt.Sprintf("--service=%s", p.ServiceTarget),
 	)
+	// Forward the harness's write directory so the plugin writes its results
+	// where the harness run summary will look for them.
+	if writeDir := viper.GetString("write-directory"); writeDir != "" {
+		cmd.Args = append(cmd.Args, fmt.Sprintf("--write-directory=%s", writeDir))
+	}
+	// Forward requirement selection given on the harness command line.
+	for _, key := range []string{"include", "exclude"} {
+		if patterns := viper.GetStringSlice(key); len(patterns) > 0 {
+			cmd.Args = append(cmd.Args, fmt.Sprintf("--%s=%s", key, strings.Join(patterns, ",")))
+		}
+	}
+	if testSuite := viper.GetString("test-suites"); testSuite != "" && testSuite != config.DefaultTestSuite {
+		cmd.Args = append(cmd.Args, fmt.Sprintf("--test-suites=%s", testSuite))
+	}
+	// The payload cache is keyed per service, so one TTL can serve them all.
+	if ttl := viper.GetDuration("payload-cache-ttl"); ttl > 0 {
+		cmd.Args = append(cmd.Args, fmt.Sprintf("--payload-cache-ttl=%s", ttl))
+	}
+	// Streamed results reach the harness through go-plugin's SyncStdout.
+	if config.StreamsResults() {
+		cmd.Args = append(cmd.Args, "--output-destination=stdout")
+	}
 	p.Command = cmd
 }
 
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// CodecRegistry returns the codecs config files are read with. It differs
+// from viper's defaults only for YAML, whose unquoted dates and times are kept
+// as written instead of being parsed into a time.Time: YAML reads 2026-01-01
+// and 2026-01-01T00:00:00Z as the same instant, while a waiver expiring on a
+// date lasts through that day.
+func CodecRegistry() viper.CodecRegistry {
+	registry := viper.NewCodecRegistry()
+	for _, format := range []string{"yaml", "yml"} {
+		_ = registry.RegisterCodec(format, literalTimesCodec{})
+	}
+	return registry
+}
+
+// literalTimesCodec is viper's YAML codec, decoding timestamps as strings.
+type literalTimesCodec struct{}
+
+func (literalTimesCodec) Encode(v map[string]any) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+
+func (literalTimesCodec) Decode(b []byte, v map[string]any) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(b, &document); err != nil {
+		return err
+	}
+	if len(document.Content) == 0 {
+		return nil
+	}
+	keepTimestampLiterals(&document)
+	return document.Decode(&v)
+}
+
+func keepTimestampLiterals(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+	for _, child := range node.Content {
+		keepTimestampLiterals(child)
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLiteralTimesCodec(t *testing.T) {
+	decoded := map[string]any{}
+	err := literalTimesCodec{}.Decode([]byte(`date: 2026-01-01
+time: 2026-01-01T00:00:00Z
+quoted: "2026-01-01"
+count: 3
+enabled: true
+list: [a, 2030-06-30]
+`), decoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want := map[string]any{
+		"date":    "2026-01-01",
+		"time":    "2026-01-01T00:00:00Z",
+		"quoted":  "2026-01-01",
+		"count":   3,
+		"enabled": true,
+		"list":    []any{"a", "2030-06-30"},
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("decoded %#v, want %#v", decoded, want)
+	}
+
+	empty := map[string]any{}
+	if err := (literalTimesCodec{}).Decode(nil, empty); err != nil || len(empty) != 0 {
+		t.Errorf("expected an empty file to decode to nothing, got %v, %v", empty, err)
+	}
+}
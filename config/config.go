@@ -20,7 +20,24 @@ import (
 
 const defaultServiceName = "overview"
 
-var allowedOutputTypes = []string{"json", "yaml", "sarif", "gemara"}
+// Output destinations. Results go to files in the write directory unless the
+// plugin is asked to stream them to stdout with "-" or "stdout".
+const (
+	FileDestination   = "file"
+	StdoutDestination = "stdout"
+)
+
+// DefaultTestSuite is the test-suites value used when none is configured.
+const DefaultTestSuite = "default"
+
+var allowedOutputTypes = []string{"json", "yaml", "sarif", "gemara", "html", "junit", "oscal"}
+
+// OutputTypes returns the results formats the output key accepts.
+func OutputTypes() []string {
+	return slices.Clone(allowedOutputTypes)
+}
+
+var allowedJUnitUnresolved = []string{"error", "skipped"}
 
 var inheritedTopLevelVarKeys = []string{
 	"ai_provider",
@@ -37,7 +54,7 @@ type Config struct {
 	LogLevel       string
 	Logger         hclog.Logger
 	Write          bool
-	Output         string
+	Output         []string // one results file is written per format
 	IncludePayload bool
 	WriteDirectory string
 	Invasive       bool
@@ -45,15 +62,53 @@ type Config struct {
 	Vars           map[string]interface{}
 	Error          error
 
+	// OutputDestination is FileDestination (the default) or StdoutDestination.
+	// When streaming, results are written to the plugin's stdout and logs
+	// stay on stderr so the stream can be piped.
+	OutputDestination string
+
 	Benchmark            bool
 	BenchmarkPayloadOnly bool
-}
 
-// Policy defines the control catalogs and applicability settings for a plugin.
-// TODO: We will want to replace this with a Gemara layer3 object now that those are ready.
-type Policy struct {
-	ControlCatalogs []string
-	Applicability   []string
+	// EvaluationWorkers bounds how many control evaluations a suite runs at
+	// once. Zero or one keeps the default serial evaluation.
+	EvaluationWorkers int
+
+	// StepTimeout bounds each assessment step and RunTimeout bounds the whole
+	// run. Zero disables the deadline.
+	StepTimeout time.Duration
+	RunTimeout  time.Duration
+
+	// JUnitUnresolved is how the junit output reports NeedsReview and Unknown
+	// results: "error" (the default) or "skipped".
+	JUnitUnresolved string
+
+	// Waivers accept known failing requirements for this service; see Waiver.
+	Waivers []Waiver
+
+	// Filter narrows the run to matching requirements; the rest are recorded
+	// as not run.
+	Filter Filter
+
+	// TestSuite names the plugin-declared set of requirements to execute.
+	// "default" runs every requirement unless the plugin declares its own
+	// default set.
+	TestSuite string
+
+	// RecordPayload and ReplayPayload name a payload fixture file. Recording
+	// saves what the loaders return; replaying skips the loaders and decodes
+	// the fixture instead, so a run can be repeated offline.
+	RecordPayload string
+	ReplayPayload string
+
+	// PayloadCacheTTL enables the payload cache: loader payloads are reused
+	// from PayloadCacheDirectory for this long. Zero disables the cache.
+	PayloadCacheTTL       time.Duration
+	PayloadCacheDirectory string
+
+	// ChangeJournalDirectory holds the journal of invasive changes applied
+	// and not yet reverted, so a crashed run's changes can be reverted later.
+	ChangeJournalDirectory string
 }
 
 // NewConfig creates a new Config instance from viper configuration.
@@ -62,11 +117,11 @@ func NewConfig(requiredVars []string) Config {
 
 	serviceName := viper.GetString("service") // the currently running service; if empty, we're probably running from core
 
-	write := viper.GetBool("write")                                         // defaults to true, but allow the user to disable file writing
-	output := strings.ToLower(strings.TrimSpace(viper.GetString("output"))) // defaults to yaml; can be set to json, sarif, or gemara
-	includePayload := viper.GetBool("include-payload")                      // defaults to false; payload is omitted unless explicitly requested
-	benchmark := viper.GetBool("benchmark")                                 // defaults to false
-	benchmarkPayloadOnly := viper.GetBool("benchmark-payload-only")         // defaults to false; loader only, skip steps
+	write := viper.GetBool("write")                                 // defaults to true, but allow the user to disable file writing
+	output := outputFormats()                                       // defaults to yaml; any of json, yaml, sarif, gemara, html, junit, or oscal
+	includePayload := viper.GetBool("include-payload")              // defaults to false; payload is omitted unless explicitly requested
+	benchmark := viper.GetBool("benchmark")                         // defaults to false
+	benchmarkPayloadOnly := viper.GetBool("benchmark-payload-only") // defaults to false; loader only, skip steps
 
 	vars := viper.GetStringMap("vars")
 	localVars := viper.GetStringMap(fmt.Sprintf("services.%s.vars", serviceName))
@@ -106,22 +161,83 @@ func NewConfig(requiredVars []string) Config {
 		invasive = topInvasive
 	}
 
-	topCatalogs := viper.GetStringSlice("policy.catalogs")
-	catalogs := viper.GetStringSlice(fmt.Sprintf("services.%s.policy.catalogs", serviceName))
-	if len(catalogs) == 0 {
-		catalogs = topCatalogs
+	policy, policyErr := loadPolicy(serviceName) // policy.document, or the policy.catalogs and policy.applicability shorthand
+	catalogs, applicability := policy.ControlCatalogs, policy.Applicability
+
+	topWorkers := viper.GetInt("evaluation-workers") // defaults to 0 (serial)
+	workers := viper.GetInt(fmt.Sprintf("services.%s.evaluation-workers", serviceName))
+	if workers == 0 {
+		workers = topWorkers
+	}
+
+	stepTimeout := viper.GetDuration(fmt.Sprintf("services.%s.step-timeout", serviceName))
+	if stepTimeout == 0 {
+		stepTimeout = viper.GetDuration("step-timeout") // defaults to 0 (no deadline)
 	}
 
-	topApplicability := viper.GetStringSlice("policy.applicability")
-	applicability := viper.GetStringSlice(fmt.Sprintf("services.%s.policy.applicability", serviceName))
-	if len(applicability) == 0 {
-		applicability = topApplicability
+	runTimeout := viper.GetDuration(fmt.Sprintf("services.%s.run-timeout", serviceName))
+	if runTimeout == 0 {
+		runTimeout = viper.GetDuration("run-timeout") // defaults to 0 (no deadline)
+	}
+
+	destination := strings.ToLower(strings.TrimSpace(viper.GetString(fmt.Sprintf("services.%s.output-destination", serviceName))))
+	if destination == "" {
+		destination = strings.ToLower(strings.TrimSpace(viper.GetString("output-destination")))
+	}
+
+	waivers, waiversErr := loadWaivers(serviceName)
+
+	filter, filterErr := loadFilter(serviceName)
+
+	testSuite := strings.TrimSpace(viper.GetString(fmt.Sprintf("services.%s.test-suites", serviceName)))
+	if testSuite == "" {
+		testSuite = strings.TrimSpace(viper.GetString("test-suites"))
+	}
+	if testSuite == "" {
+		testSuite = DefaultTestSuite
+	}
+
+	recordPayload := viper.GetString(fmt.Sprintf("services.%s.record-payload", serviceName))
+	if recordPayload == "" {
+		recordPayload = viper.GetString("record-payload")
+	}
+	replayPayload := viper.GetString(fmt.Sprintf("services.%s.replay-payload", serviceName))
+	if replayPayload == "" {
+		replayPayload = viper.GetString("replay-payload")
+	}
+
+	payloadCacheTTL := viper.GetDuration(fmt.Sprintf("services.%s.payload-cache-ttl", serviceName))
+	if payloadCacheTTL == 0 {
+		payloadCacheTTL = viper.GetDuration("payload-cache-ttl") // defaults to 0 (no cache)
+	}
+	payloadCacheDir := viper.GetString(fmt.Sprintf("services.%s.payload-cache-directory", serviceName))
+	if payloadCacheDir == "" {
+		payloadCacheDir = viper.GetString("payload-cache-directory")
+	}
+	if payloadCacheDir == "" {
+		payloadCacheDir = defaultPayloadCachePath()
+	}
+
+	changeJournalDir := viper.GetString(fmt.Sprintf("services.%s.change-journal-directory", serviceName))
+	if changeJournalDir == "" {
+		changeJournalDir = viper.GetString("change-journal-directory")
+	}
+	if changeJournalDir == "" {
+		changeJournalDir = defaultChangeJournalPath()
+	}
+
+	junitUnresolved := strings.ToLower(strings.TrimSpace(viper.GetString(fmt.Sprintf("services.%s.junit-unresolved", serviceName))))
+	if junitUnresolved == "" {
+		junitUnresolved = strings.ToLower(strings.TrimSpace(viper.GetString("junit-unresolved")))
 	}
 
 	if serviceName != "" && (len(applicability) == 0 || len(catalogs) == 0) {
 		errString = fmt.Sprintf("invalid policy for service %s. applicability=%v catalogs=%v",
 			serviceName, len(applicability), len(catalogs))
 	}
+	if policyErr != nil {
+		errString = fmt.Sprintf("invalid policy for service %s: %v", serviceName, policyErr)
+	}
 
 	var missingVars []string
 	for _, key := range requiredVars {
@@ -133,10 +249,56 @@ func NewConfig(requiredVars []string) Config {
 		errString = fmt.Sprintf("missing required variables: %v", missingVars)
 	}
 
-	if output == "" {
-		output = "yaml"
-	} else if ok := slices.Contains(allowedOutputTypes, output); !ok {
-		errString = "bad output type, allowed output types are json, yaml, sarif, or gemara"
+	if workers < 0 {
+		errString = fmt.Sprintf("evaluation-workers must not be negative, got %d", workers)
+	}
+
+	if stepTimeout < 0 || runTimeout < 0 {
+		errString = fmt.Sprintf("timeouts must not be negative, got step-timeout=%s run-timeout=%s", stepTimeout, runTimeout)
+	}
+
+	if waiversErr != nil {
+		errString = waiversErr.Error()
+	}
+
+	if filterErr != nil {
+		errString = filterErr.Error()
+	}
+
+	if recordPayload != "" && replayPayload != "" {
+		errString = "record-payload and replay-payload cannot be used together"
+	}
+
+	if payloadCacheTTL < 0 {
+		errString = fmt.Sprintf("payload-cache-ttl must not be negative, got %s", payloadCacheTTL)
+	}
+
+	if junitUnresolved == "" {
+		junitUnresolved = "error"
+	} else if !slices.Contains(allowedJUnitUnresolved, junitUnresolved) {
+		errString = fmt.Sprintf("junit-unresolved must be error or skipped, got %q", junitUnresolved)
+	}
+
+	if len(output) == 0 {
+		output = []string{"yaml"}
+	}
+	for _, format := range output {
+		if !slices.Contains(allowedOutputTypes, format) {
+			errString = "bad output type, allowed output types are json, yaml, sarif, gemara, html, junit, or oscal"
+		}
+	}
+
+	switch destination {
+	case "", FileDestination:
+		destination = FileDestination
+	case "-", StdoutDestination:
+		destination = StdoutDestination
+		if len(output) > 1 {
+			// concatenated documents of different formats could not be parsed
+			errString = fmt.Sprintf("output-destination stdout takes a single output type, got %v", output)
+		}
+	default:
+		errString = fmt.Sprintf("output-destination must be file, stdout or -, got %q", destination)
 	}
 
 	var err error
@@ -145,26 +307,36 @@ func NewConfig(requiredVars []string) Config {
 	}
 
 	config := Config{
-		ServiceName:          serviceName,
-		LogLevel:             loglevel,
-		WriteDirectory:       writeDir,
-		Write:                write,
-		Output:               output,
-		IncludePayload:       includePayload,
-		Invasive:             invasive,
-		Benchmark:            benchmark,
-		BenchmarkPayloadOnly: benchmarkPayloadOnly,
-		Policy: Policy{
-			ControlCatalogs: catalogs,
-			Applicability:   applicability,
-		},
-		Vars:  vars,
-		Error: err,
+		ServiceName:            serviceName,
+		LogLevel:               loglevel,
+		WriteDirectory:         writeDir,
+		Write:                  write,
+		Output:                 output,
+		IncludePayload:         includePayload,
+		OutputDestination:      destination,
+		Invasive:               invasive,
+		Benchmark:              benchmark,
+		BenchmarkPayloadOnly:   benchmarkPayloadOnly,
+		EvaluationWorkers:      workers,
+		StepTimeout:            stepTimeout,
+		RunTimeout:             runTimeout,
+		JUnitUnresolved:        junitUnresolved,
+		Waivers:                waivers,
+		Filter:                 filter,
+		TestSuite:              testSuite,
+		RecordPayload:          recordPayload,
+		ReplayPayload:          replayPayload,
+		PayloadCacheTTL:        payloadCacheTTL,
+		PayloadCacheDirectory:  payloadCacheDir,
+		ChangeJournalDirectory: changeJournalDir,
+		Policy:                 policy,
+		Vars:                   vars,
+		Error:                  err,
 	}
 	if serviceName == "" {
 		serviceName = defaultServiceName
 	}
-	config.SetupLogging(serviceName, output == "json")
+	config.SetupLogging(serviceName, jsonLogs(output))
 	printSanitizedVars(config.Logger, vars)
 	config.Logger.Trace("Creating a new config instance for service",
 		"serviceName", serviceName,
@@ -175,6 +347,19 @@ func NewConfig(requiredVars []string) Config {
 		"applicability", applicability,
 		"control-catalogs", catalogs,
 		"output", output,
+		"output-destination", destination,
+		"evaluation-workers", workers,
+		"step-timeout", stepTimeout,
+		"run-timeout", runTimeout,
+		"junit-unresolved", junitUnresolved,
+		"include", filter.Include,
+		"exclude", filter.Exclude,
+		"test-suite", testSuite,
+		"waivers", len(waivers),
+		"record-payload", recordPayload,
+		"replay-payload", replayPayload,
+		"payload-cache-ttl", payloadCacheTTL,
+		"payload-cache-directory", payloadCacheDir,
 	)
 	return config
 }
@@ -205,6 +390,22 @@ func sanitizeVars(vars map[string]interface{}) map[string]interface{} {
 	return sanitizedVars
 }
 
+// outputFormats reads the requested output formats. The key may be a list,
+// a comma-separated string, or a repeated flag; formats are lowercased and
+// duplicates dropped so each file is written once.
+func outputFormats() []string {
+	var formats []string
+	for _, entry := range viper.GetStringSlice("output") {
+		for _, format := range strings.Split(entry, ",") {
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != "" && !slices.Contains(formats, format) {
+				formats = append(formats, format)
+			}
+		}
+	}
+	return formats
+}
+
 func defaultWritePath() string {
 	home, err := os.UserHomeDir()
 	datetime := time.Now().Local().Format(time.RFC3339)
@@ -215,6 +416,27 @@ func defaultWritePath() string {
 	return filepath.Join(home, ".privateer", "logs", dirName)
 }
 
+// defaultPayloadCachePath is where the payload cache lives when enabled,
+// under the privateer home directory.
+func defaultPayloadCachePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".privateer", "cache", "payloads")
+}
+
+// defaultChangeJournalPath is where change journals live. Unlike the default
+// write directory it does not change between runs, so a later run can find
+// the journal of one that crashed.
+func defaultChangeJournalPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".privateer", "journals")
+}
+
 // SetupLogging configures logging for the plugin with the given name and format.
 func (c *Config) SetupLogging(name string, jsonFormat bool) {
 	var logFilePath string
@@ -241,6 +463,12 @@ func (c *Config) SetupLogging(name string, jsonFormat bool) {
 	c.Logger = logger
 }
 
+// jsonLogs reports whether logs are written as JSON: only when json is the
+// sole output, so adding another results format leaves the log format as is.
+func jsonLogs(output []string) bool {
+	return len(output) == 1 && output[0] == "json"
+}
+
 func (c *Config) setupLoggingFilesAndDirectories(logFilePath string) io.Writer {
 	// Create log file and directory if it doesn't exist
 	if _, err := os.Stat(logFilePath); os.IsNotExist(err) {
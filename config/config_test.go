@@ -5,8 +5,10 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/pflag"
@@ -42,7 +44,7 @@ var testConfigs = []struct {
 	writeDirSet          bool
 	writeSet             bool
 	expectedLogLevel     string
-	expectedOutput       string
+	expectedOutput       []string
 	expectedError        string
 	expectedWrite        bool
 }{
@@ -305,7 +307,7 @@ services:
 		runningServiceName:   "my-service-1",
 		runningApplicability: []string{"tlp_green"},
 		requiredVars:         []string{},
-		expectedOutput:       "yaml",
+		expectedOutput:       []string{"yaml"},
 		config: `
 services:
   my-service-1:
@@ -318,9 +320,37 @@ services:
 		runningServiceName:   "my-service-1",
 		runningApplicability: []string{"tlp_green"},
 		requiredVars:         []string{},
-		expectedOutput:       "json",
+		expectedOutput:       []string{"json"},
 		config: `
 output: json
+services:
+  my-service-1:
+    policy:
+      catalogs:
+        - FINOS-CCC
+      applicability: ["tlp_green"]
+`}, {
+		testName:             "Good - several output types as a list",
+		runningServiceName:   "my-service-1",
+		runningApplicability: []string{"tlp_green"},
+		requiredVars:         []string{},
+		expectedOutput:       []string{"sarif", "yaml"},
+		config: `
+output: [sarif, YAML, sarif]
+services:
+  my-service-1:
+    policy:
+      catalogs:
+        - FINOS-CCC
+      applicability: ["tlp_green"]
+`}, {
+		testName:             "Good - several output types comma-separated",
+		runningServiceName:   "my-service-1",
+		runningApplicability: []string{"tlp_green"},
+		requiredVars:         []string{},
+		expectedOutput:       []string{"junit", "html"},
+		config: `
+output: junit, html
 services:
   my-service-1:
     policy:
@@ -332,7 +362,7 @@ services:
 		runningServiceName:   "my-service-1",
 		runningApplicability: []string{"tlp_green"},
 		requiredVars:         []string{},
-		expectedOutput:       "yaml",
+		expectedOutput:       []string{"yaml"},
 		config: `
 output: yaml
 services:
@@ -346,9 +376,23 @@ services:
 		runningServiceName:   "my-service-1",
 		runningApplicability: []string{"tlp_green"},
 		requiredVars:         []string{},
-		expectedError:        "bad output type, allowed output types are json, yaml, sarif, or gemara",
+		expectedError:        "bad output type, allowed output types are json, yaml, sarif, gemara, html, junit, or oscal",
 		config: `
 output: bad
+services:
+  my-service-1:
+    policy:
+      catalogs:
+        - FINOS-CCC
+      applicability: ["tlp_green"]
+`}, {
+		testName:             "Bad - one bad output type in a list",
+		runningServiceName:   "my-service-1",
+		runningApplicability: []string{"tlp_green"},
+		requiredVars:         []string{},
+		expectedError:        "bad output type, allowed output types are json, yaml, sarif, gemara, html, junit, or oscal",
+		config: `
+output: [yaml, bad]
 services:
   my-service-1:
     policy:
@@ -495,8 +539,8 @@ func TestNewConfig(t *testing.T) {
 				t.Errorf("expected log level to be set to '%s', but got '%s'", tt.expectedLogLevel, c.LogLevel)
 			}
 
-			if tt.expectedOutput != "" && c.Output != tt.expectedOutput {
-				t.Errorf("expected output to be '%s', but got '%s'", tt.expectedOutput, c.Output)
+			if tt.expectedOutput != nil && !slices.Equal(c.Output, tt.expectedOutput) {
+				t.Errorf("expected output to be %v, but got %v", tt.expectedOutput, c.Output)
 			}
 
 			if tt.writeSet && tt.expectedWrite != c.Write {
@@ -561,6 +605,77 @@ func TestNewConfig_DoesNotInheritBoundFlagDefaultIntoVars(t *testing.T) {
 	}
 }
 
+func TestNewConfig_EvaluationWorkers(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		expected      int
+		expectedError string
+	}{
+		{name: "default is serial", expected: 0},
+		{name: "top level", config: "evaluation-workers: 4", expected: 4},
+		{name: "service overrides top level", config: "evaluation-workers: 4\nservices:\n  my-service-1:\n    evaluation-workers: 2", expected: 2},
+		{name: "negative rejected", config: "evaluation-workers: -1", expected: -1, expectedError: "evaluation-workers must not be negative, got -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			viper.Set("service", "my-service-1")
+			viper.Set("services.my-service-1.policy.catalogs", []string{"FINOS-CCC"})
+			viper.Set("services.my-service-1.policy.applicability", []string{"tlp_green"})
+
+			c := NewConfig(nil)
+			if c.EvaluationWorkers != tt.expected {
+				t.Errorf("EvaluationWorkers = %d, want %d", c.EvaluationWorkers, tt.expected)
+			}
+			if tt.expectedError == "" && c.Error != nil {
+				t.Errorf("expected no error, got %v", c.Error)
+			} else if tt.expectedError != "" && (c.Error == nil || c.Error.Error() != tt.expectedError) {
+				t.Errorf("expected error %q, got %v", tt.expectedError, c.Error)
+			}
+		})
+	}
+}
+
+func TestNewConfig_Timeouts(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(bytes.NewBufferString(`
+step-timeout: 30s
+run-timeout: 10m
+services:
+  my-service-1:
+    step-timeout: 5s
+    policy:
+      catalogs: ["FINOS-CCC"]
+      applicability: ["tlp_green"]
+`))
+	if err != nil {
+		t.Fatalf("error reading config: %v", err)
+	}
+	viper.Set("service", "my-service-1")
+
+	c := NewConfig(nil)
+	if c.Error != nil {
+		t.Fatalf("expected no error, got %v", c.Error)
+	}
+	if c.StepTimeout != 5*time.Second {
+		t.Errorf("StepTimeout = %s, want the service override of 5s", c.StepTimeout)
+	}
+	if c.RunTimeout != 10*time.Minute {
+		t.Errorf("RunTimeout = %s, want the top-level 10m", c.RunTimeout)
+	}
+
+	viper.Set("run-timeout", "-1s")
+	if c := NewConfig(nil); c.Error == nil || !strings.Contains(c.Error.Error(), "timeouts must not be negative") {
+		t.Errorf("expected a negative timeout to be rejected, got %v", c.Error)
+	}
+}
+
 func TestDefaultWritePath(t *testing.T) {
 	path := defaultWritePath()
 
@@ -644,6 +759,23 @@ func TestSetupLogging(t *testing.T) {
 	}
 }
 
+func TestJSONLogs(t *testing.T) {
+	tests := []struct {
+		output []string
+		want   bool
+	}{
+		{output: []string{"json"}, want: true},
+		{output: []string{"yaml"}},
+		{output: []string{"yaml", "json"}},
+		{output: []string{"json", "sarif"}},
+	}
+	for _, tt := range tests {
+		if got := jsonLogs(tt.output); got != tt.want {
+			t.Errorf("jsonLogs(%v) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
 func TestSetupLoggingOverviewSkipsFileCreation(t *testing.T) {
 	tmpDir := path.Join(os.TempDir(), "privateer-test-overview")
 	defer cleanupTmpDir(t, tmpDir)
@@ -746,3 +878,225 @@ func TestSetupLoggingFilesAndDirectories(t *testing.T) {
 		t.Error("expected writer to be set")
 	}
 }
+
+func TestNewConfig_JUnitUnresolved(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		want      string
+		wantError string
+	}{
+		{name: "defaults to error", want: "error"},
+		{name: "top level", config: "junit-unresolved: skipped\n", want: "skipped"},
+		{name: "service override", config: "junit-unresolved: skipped\nservices:\n  my-service-1:\n    junit-unresolved: Error\n", want: "error"},
+		{name: "rejects other values", config: "junit-unresolved: failure\n", wantError: `junit-unresolved must be error or skipped, got "failure"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			viper.Set("service", "my-service-1")
+			viper.Set("policy.catalogs", []string{"FINOS-CCC"})
+			viper.Set("policy.applicability", []string{"tlp_green"})
+
+			c := NewConfig(nil)
+			if tt.wantError != "" {
+				if c.Error == nil || c.Error.Error() != tt.wantError {
+					t.Errorf("expected error %q, got %v", tt.wantError, c.Error)
+				}
+				return
+			}
+			if c.Error != nil {
+				t.Fatalf("expected no error, got %v", c.Error)
+			}
+			if c.JUnitUnresolved != tt.want {
+				t.Errorf("JUnitUnresolved = %q, want %q", c.JUnitUnresolved, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConfig_OutputDestination(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		want      string
+		wantError string
+	}{
+		{name: "defaults to file", want: FileDestination},
+		{name: "dash streams to stdout", config: "output-destination: \"-\"\n", want: StdoutDestination},
+		{name: "service override", config: "output-destination: file\nservices:\n  my-service-1:\n    output-destination: STDOUT\n", want: StdoutDestination},
+		{name: "stdout takes one output type", config: "output-destination: stdout\noutput: [yaml, sarif]\n", wantError: "output-destination stdout takes a single output type, got [yaml sarif]"},
+		{name: "rejects other values", config: "output-destination: s3\n", wantError: `output-destination must be file, stdout or -, got "s3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			viper.Set("service", "my-service-1")
+			viper.Set("policy.catalogs", []string{"FINOS-CCC"})
+			viper.Set("policy.applicability", []string{"tlp_green"})
+
+			c := NewConfig(nil)
+			if tt.wantError != "" {
+				if c.Error == nil || c.Error.Error() != tt.wantError {
+					t.Errorf("expected error %q, got %v", tt.wantError, c.Error)
+				}
+				return
+			}
+			if c.Error != nil {
+				t.Fatalf("expected no error, got %v", c.Error)
+			}
+			if c.OutputDestination != tt.want {
+				t.Errorf("OutputDestination = %q, want %q", c.OutputDestination, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConfig_TestSuite(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{name: "defaults to default", want: DefaultTestSuite},
+		{name: "top level", config: "test-suites: quick\n", want: "quick"},
+		{name: "service override", config: "test-suites: quick\nservices:\n  my-service-1:\n    test-suites: full\n", want: "full"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			viper.Set("service", "my-service-1")
+			viper.Set("policy.catalogs", []string{"FINOS-CCC"})
+			viper.Set("policy.applicability", []string{"tlp_green"})
+
+			c := NewConfig(nil)
+			if c.Error != nil {
+				t.Fatalf("expected no error, got %v", c.Error)
+			}
+			if c.TestSuite != tt.want {
+				t.Errorf("TestSuite = %q, want %q", c.TestSuite, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConfig_PayloadFixture(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		wantRecord string
+		wantReplay string
+		wantErr    bool
+	}{
+		{name: "neither"},
+		{name: "top level record", config: "record-payload: fixture.json\n", wantRecord: "fixture.json"},
+		{name: "service override", config: "replay-payload: top.json\nservices:\n  my-service-1:\n    replay-payload: mine.json\n", wantReplay: "mine.json"},
+		{name: "both set", config: "record-payload: a.json\nreplay-payload: b.json\n", wantRecord: "a.json", wantReplay: "b.json", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			viper.Set("service", "my-service-1")
+			viper.Set("policy.catalogs", []string{"FINOS-CCC"})
+			viper.Set("policy.applicability", []string{"tlp_green"})
+
+			c := NewConfig(nil)
+			if (c.Error != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", c.Error, tt.wantErr)
+			}
+			if c.RecordPayload != tt.wantRecord || c.ReplayPayload != tt.wantReplay {
+				t.Errorf("RecordPayload, ReplayPayload = %q, %q, want %q, %q", c.RecordPayload, c.ReplayPayload, tt.wantRecord, tt.wantReplay)
+			}
+		})
+	}
+}
+
+func TestNewConfig_PayloadCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantTTL time.Duration
+		wantDir string
+		wantErr bool
+	}{
+		{name: "off by default"},
+		{name: "top level", config: "payload-cache-ttl: 1h\npayload-cache-directory: /tmp/cache\n", wantTTL: time.Hour, wantDir: "/tmp/cache"},
+		{name: "service override", config: "payload-cache-ttl: 1h\nservices:\n  my-service-1:\n    payload-cache-ttl: 10m\n", wantTTL: 10 * time.Minute},
+		{name: "negative", config: "payload-cache-ttl: -1m\n", wantTTL: -time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			viper.Set("service", "my-service-1")
+			viper.Set("policy.catalogs", []string{"FINOS-CCC"})
+			viper.Set("policy.applicability", []string{"tlp_green"})
+
+			c := NewConfig(nil)
+			if (c.Error != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", c.Error, tt.wantErr)
+			}
+			if c.PayloadCacheTTL != tt.wantTTL {
+				t.Errorf("PayloadCacheTTL = %s, want %s", c.PayloadCacheTTL, tt.wantTTL)
+			}
+			wantDir := tt.wantDir
+			if wantDir == "" {
+				wantDir = defaultPayloadCachePath()
+			}
+			if c.PayloadCacheDirectory != wantDir {
+				t.Errorf("PayloadCacheDirectory = %q, want %q", c.PayloadCacheDirectory, wantDir)
+			}
+		})
+	}
+}
+
+func TestNewConfig_ChangeJournalDirectory(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantDir string
+	}{
+		{name: "default", wantDir: defaultChangeJournalPath()},
+		{name: "top level", config: "change-journal-directory: /tmp/journals\n", wantDir: "/tmp/journals"},
+		{name: "service override", config: "change-journal-directory: /tmp/journals\nservices:\n  my-service-1:\n    change-journal-directory: /tmp/mine\n", wantDir: "/tmp/mine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			viper.Set("service", "my-service-1")
+			viper.Set("policy.catalogs", []string{"FINOS-CCC"})
+			viper.Set("policy.applicability", []string{"tlp_green"})
+
+			c := NewConfig(nil)
+			if c.Error != nil {
+				t.Fatalf("unexpected error: %v", c.Error)
+			}
+			if c.ChangeJournalDirectory != tt.wantDir {
+				t.Errorf("ChangeJournalDirectory = %q, want %q", c.ChangeJournalDirectory, tt.wantDir)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Filter narrows a run to part of the selected catalogs, e.g. to debug a
+// single requirement without running the rest. Each pattern is a control id,
+// requirement id, or control family (the control's catalog group), and may
+// use glob wildcards such as CCC.ObjStor.C0*. An empty Filter selects
+// everything.
+type Filter struct {
+	Include []string
+	Exclude []string
+}
+
+// loadFilter reads the "include" and "exclude" keys, the service's own value
+// winning over the top level. Entries may be comma-separated.
+func loadFilter(serviceName string) (Filter, error) {
+	filter := Filter{
+		Include: filterPatterns(serviceName, "include"),
+		Exclude: filterPatterns(serviceName, "exclude"),
+	}
+	for _, pattern := range append(append([]string{}, filter.Include...), filter.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Filter{}, fmt.Errorf("bad include/exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return filter, nil
+}
+
+func filterPatterns(serviceName, key string) []string {
+	entries := viper.GetStringSlice(fmt.Sprintf("services.%s.%s", serviceName, key))
+	if len(entries) == 0 {
+		entries = viper.GetStringSlice(key)
+	}
+	var patterns []string
+	for _, entry := range entries {
+		for _, pattern := range strings.Split(entry, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+// Selects reports whether the filter keeps the requirement, and if not, why.
+// An exclude pattern wins over an include pattern.
+func (f Filter) Selects(family, controlId, requirementId string) (bool, string) {
+	if pattern, ok := matchAny(f.Exclude, family, controlId, requirementId); ok {
+		return false, fmt.Sprintf("excluded by filter %q", pattern)
+	}
+	if len(f.Include) > 0 {
+		if _, ok := matchAny(f.Include, family, controlId, requirementId); !ok {
+			return false, "not matched by the include filter"
+		}
+	}
+	return true, ""
+}
+
+// matchAny returns the first pattern matching any of the ids.
+func matchAny(patterns []string, ids ...string) (string, bool) {
+	for _, pattern := range patterns {
+		for _, id := range ids {
+			if id == "" {
+				continue
+			}
+			if matched, _ := path.Match(pattern, id); matched {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		wantInclude []string
+		wantExclude []string
+		wantError   string
+	}{
+		{name: "none configured"},
+		{
+			name:        "top level, comma-separated",
+			config:      "include: [\"CCC.ObjStor.C01, CCC.ObjStor.C02.*\"]\nexclude: [CCC.ObjStor.C02.TR02]\n",
+			wantInclude: []string{"CCC.ObjStor.C01", "CCC.ObjStor.C02.*"},
+			wantExclude: []string{"CCC.ObjStor.C02.TR02"},
+		},
+		{
+			name:        "service value wins",
+			config:      "include: [A]\nservices:\n  my-service-1:\n    include: [B]\n",
+			wantInclude: []string{"B"},
+		},
+		{
+			name:      "bad glob",
+			config:    "exclude: [\"CCC.[\"]\n",
+			wantError: `bad include/exclude pattern "CCC.["`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			filter, err := loadFilter("my-service-1")
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(filter.Include, ",") != strings.Join(tt.wantInclude, ",") {
+				t.Errorf("include = %v, want %v", filter.Include, tt.wantInclude)
+			}
+			if strings.Join(filter.Exclude, ",") != strings.Join(tt.wantExclude, ",") {
+				t.Errorf("exclude = %v, want %v", filter.Exclude, tt.wantExclude)
+			}
+		})
+	}
+}
+
+func TestFilter_Selects(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     Filter
+		family     string
+		control    string
+		req        string
+		wantReason string
+	}{
+		{name: "empty filter", control: "C.C01", req: "C.C01.TR01"},
+		{name: "include by requirement", filter: Filter{Include: []string{"C.C01.TR01"}}, control: "C.C01", req: "C.C01.TR01"},
+		{name: "include by control", filter: Filter{Include: []string{"C.C01"}}, control: "C.C01", req: "C.C01.TR02"},
+		{name: "include by family", filter: Filter{Include: []string{"Data*"}}, family: "DataProtection", control: "C.C01", req: "C.C01.TR01"},
+		{name: "include glob", filter: Filter{Include: []string{"C.C0?.TR01"}}, control: "C.C03", req: "C.C03.TR01"},
+		{name: "not included", filter: Filter{Include: []string{"C.C02"}}, control: "C.C01", req: "C.C01.TR01", wantReason: "not matched by the include filter"},
+		{
+			name:    "exclude wins over include",
+			filter:  Filter{Include: []string{"C.C01"}, Exclude: []string{"C.C01.TR02"}},
+			control: "C.C01", req: "C.C01.TR02",
+			wantReason: `excluded by filter "C.C01.TR02"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, reason := tt.filter.Selects(tt.family, tt.control, tt.req)
+			if selected != (tt.wantReason == "") || reason != tt.wantReason {
+				t.Errorf("Selects = %v %q, want reason %q", selected, reason, tt.wantReason)
+			}
+		})
+	}
+}
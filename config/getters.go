@@ -23,6 +23,36 @@ func AutoInstall() bool {
 	return viper.GetBool("autoinstall")
 }
 
+// ParallelPlugins returns how many plugins a run may execute at once (the
+// "parallel-plugins" config key, also settable via the PVTR_PARALLEL_PLUGINS
+// environment variable). Values below 2 keep the default of one plugin at a time.
+// It reads from the same viper state as NewConfig (e.g. after command.ReadConfig()).
+func ParallelPlugins() int {
+	if n := viper.GetInt("parallel-plugins"); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// StreamsResults reports whether the top-level "output-destination" key asks
+// plugins to write results to stdout ("-" or "stdout") instead of to files, in
+// which case a harness must keep its own output off stdout.
+// It reads from the same viper state as NewConfig (e.g. after command.ReadConfig()).
+func StreamsResults() bool {
+	destination := strings.ToLower(strings.TrimSpace(viper.GetString("output-destination")))
+	return destination == "-" || destination == StdoutDestination
+}
+
+// OutputFormats returns the results formats the top-level "output" key asks
+// plugins to write, lowercased and deduplicated; yaml when none is set.
+// It reads from the same viper state as NewConfig (e.g. after command.ReadConfig()).
+func OutputFormats() []string {
+	if formats := outputFormats(); len(formats) > 0 {
+		return formats
+	}
+	return []string{"yaml"}
+}
+
 // GetServices returns the services map from config (service name -> service config).
 // It reads from the same viper state as NewConfig (e.g. after command.ReadConfig()).
 func GetServices() map[string]interface{} {
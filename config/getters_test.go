@@ -252,6 +252,39 @@ func TestAutoInstall_FromEnv(t *testing.T) {
 	}
 }
 
+func TestParallelPlugins(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	cases := map[int]int{
+		0:  1, // unset runs one plugin at a time
+		-3: 1, // nonsense values fall back to serial
+		1:  1,
+		4:  4,
+	}
+	for in, want := range cases {
+		viper.Set("parallel-plugins", in)
+		if got := ParallelPlugins(); got != want {
+			t.Errorf("ParallelPlugins() with %d = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestStreamsResults(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	cases := map[string]bool{
+		"":       false,
+		"file":   false,
+		"-":      true,
+		"stdout": true,
+		"STDOUT": true,
+	}
+	for in, want := range cases {
+		viper.Set("output-destination", in)
+		if got := StreamsResults(); got != want {
+			t.Errorf("StreamsResults() with %q = %v, want %v", in, got, want)
+		}
+	}
+}
+
 func TestGetServiceVersion_NormalizesLeadingV(t *testing.T) {
 	t.Cleanup(viper.Reset)
 	cases := map[string]string{
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/viper"
+)
+
+// shorthandPolicyId identifies the policy built from the policy.catalogs and
+// policy.applicability keys when no document is configured.
+const shorthandPolicyId = "privateer-config-policy"
+
+// Policy is the Gemara Policy a service is evaluated against. ControlCatalogs
+// and Applicability are derived from Document (the catalogs it imports and the
+// applicability groups in scope) and kept as fields for existing callers.
+type Policy struct {
+	ControlCatalogs []string
+	Applicability   []string
+
+	// Document is the policy.document the service references, or one built
+	// from the policy.catalogs and policy.applicability shorthand keys. It is
+	// nil only for a Policy constructed directly, which selects everything.
+	Document *gemara.Policy
+}
+
+// loadPolicy resolves the service's policy. A policy.document under the
+// service wins over a top-level one; either replaces the shorthand keys. The
+// document is a path to a Gemara Policy YAML or JSON file (relative paths are
+// resolved against the config file) or the policy embedded inline.
+func loadPolicy(serviceName string) (Policy, error) {
+	document := viper.Get(fmt.Sprintf("services.%s.policy.document", serviceName))
+	if document == nil {
+		document = viper.Get("policy.document")
+	}
+	if document == nil {
+		return shorthandPolicy(serviceName), nil
+	}
+
+	var data []byte
+	var err error
+	switch document := document.(type) {
+	case string:
+		data, err = os.ReadFile(policyPath(document))
+		if err != nil {
+			return Policy{}, fmt.Errorf("reading policy document: %w", err)
+		}
+	case map[string]any:
+		data, err = yaml.Marshal(document)
+		if err != nil {
+			return Policy{}, fmt.Errorf("reading embedded policy document: %w", err)
+		}
+	default:
+		return Policy{}, fmt.Errorf("policy.document must be a file path or an embedded policy, got %T", document)
+	}
+
+	var doc gemara.Policy
+	// goccy/go-yaml so gemara enum types are unmarshaled via their
+	// UnmarshalYAML([]byte) methods; JSON is valid YAML.
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Policy{}, fmt.Errorf("parsing policy document: %w", err)
+	}
+	return policyFromDocument(&doc)
+}
+
+// policyPath resolves a relative document path against the config file.
+func policyPath(path string) string {
+	if filepath.IsAbs(path) || viper.ConfigFileUsed() == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(viper.ConfigFileUsed()), path)
+}
+
+// policyFromDocument validates a Gemara Policy and derives the catalogs and
+// applicability that drive suite selection.
+func policyFromDocument(doc *gemara.Policy) (Policy, error) {
+	if doc.Metadata.Type != gemara.InvalidArtifact && doc.Metadata.Type != gemara.PolicyArtifact {
+		return Policy{}, fmt.Errorf("policy document %s is a %s, not a Policy", doc.Metadata.Id, doc.Metadata.Type)
+	}
+	policy := Policy{Document: doc, Applicability: doc.Scope.In.Groups}
+	for _, imported := range doc.Imports.Catalogs {
+		if imported.ReferenceId == "" {
+			return Policy{}, fmt.Errorf("policy document %s imports a catalog without a reference-id", doc.Metadata.Id)
+		}
+		if !slices.Contains(policy.ControlCatalogs, imported.ReferenceId) {
+			policy.ControlCatalogs = append(policy.ControlCatalogs, imported.ReferenceId)
+		}
+	}
+	for _, plan := range doc.Adherence.AssessmentPlans {
+		if plan.RequirementId == "" {
+			return Policy{}, fmt.Errorf("policy document %s has assessment plan %q without a requirement-id", doc.Metadata.Id, plan.Id)
+		}
+	}
+	return policy, nil
+}
+
+// shorthandPolicy builds the policy from the policy.catalogs and
+// policy.applicability keys, each read from the service before the top level.
+func shorthandPolicy(serviceName string) Policy {
+	catalogs := viper.GetStringSlice(fmt.Sprintf("services.%s.policy.catalogs", serviceName))
+	if len(catalogs) == 0 {
+		catalogs = viper.GetStringSlice("policy.catalogs")
+	}
+	applicability := viper.GetStringSlice(fmt.Sprintf("services.%s.policy.applicability", serviceName))
+	if len(applicability) == 0 {
+		applicability = viper.GetStringSlice("policy.applicability")
+	}
+
+	doc := &gemara.Policy{
+		Title:    "Policy from policy.catalogs and policy.applicability",
+		Metadata: gemara.Metadata{Id: shorthandPolicyId, Type: gemara.PolicyArtifact},
+		Scope:    gemara.Scope{In: gemara.Dimensions{Groups: applicability}},
+	}
+	for _, catalog := range catalogs {
+		doc.Imports.Catalogs = append(doc.Imports.Catalogs, gemara.CatalogImport{ReferenceId: catalog})
+	}
+	return Policy{ControlCatalogs: catalogs, Applicability: applicability, Document: doc}
+}
+
+// Selects reports whether the policy has the requirement assessed. When it
+// does not, the reason names the policy and why: the control or requirement
+// is excluded from its catalog import, a modification removes it, or the
+// policy lists assessment plans and none is for this requirement.
+func (p Policy) Selects(catalogId, controlId, requirementId string) (bool, string) {
+	if p.Document == nil {
+		return true, ""
+	}
+	id := p.Document.Metadata.Id
+	for _, imported := range p.Document.Imports.Catalogs {
+		if imported.ReferenceId != catalogId {
+			continue
+		}
+		for _, excluded := range imported.Exclusions {
+			if excluded == controlId || excluded == requirementId {
+				return false, fmt.Sprintf("excluded by policy %s", id)
+			}
+		}
+		for _, modification := range imported.AssessmentRequirementModifications {
+			if modification.TargetId == requirementId && modification.ModificationType == gemara.ModRemove {
+				return false, fmt.Sprintf("removed by policy %s: %s", id, modification.ModificationRationale)
+			}
+		}
+	}
+	if plans := p.Document.Adherence.AssessmentPlans; len(plans) > 0 {
+		for _, plan := range plans {
+			if plan.RequirementId == requirementId {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("policy %s has no assessment plan for this requirement", id)
+	}
+	return true, ""
+}
+
+// RequirementApplicability returns the requirement's applicability, replaced
+// by the policy when one of its modifications sets new applicability.
+func (p Policy) RequirementApplicability(catalogId, requirementId string, applicability []string) []string {
+	if p.Document == nil {
+		return applicability
+	}
+	for _, imported := range p.Document.Imports.Catalogs {
+		if imported.ReferenceId != catalogId {
+			continue
+		}
+		for _, modification := range imported.AssessmentRequirementModifications {
+			if modification.TargetId == requirementId && modification.ModificationType != gemara.ModRemove && len(modification.Applicability) > 0 {
+				return modification.Applicability
+			}
+		}
+	}
+	return applicability
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/spf13/viper"
+)
+
+const testPolicyDocument = `title: Object storage policy
+metadata:
+  id: storage-policy
+  type: Policy
+scope:
+  in:
+    groups: [tlp-green, tlp-amber]
+imports:
+  catalogs:
+    - reference-id: CCC.ObjStor
+      exclusions: [CCC.ObjStor.C02]
+adherence:
+  assessment-plans:
+    - id: plan-1
+      requirement-id: CCC.ObjStor.C01.TR01
+`
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(testPolicyDocument), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "guidance.yaml"), []byte("metadata:\n  id: g\n  type: ControlCatalog\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name              string
+		config            string
+		wantId            string
+		wantCatalogs      []string
+		wantApplicability []string
+		wantError         string
+	}{
+		{
+			name: "shorthand keys",
+			config: `policy:
+  catalogs: [CCC.ObjStor]
+  applicability: [tlp-clear]
+services:
+  my-service-1:
+    policy:
+      applicability: [tlp-red]
+`,
+			wantId:            shorthandPolicyId,
+			wantCatalogs:      []string{"CCC.ObjStor"},
+			wantApplicability: []string{"tlp-red"},
+		},
+		{
+			name: "document path relative to the config file replaces the shorthand",
+			config: `policy:
+  catalogs: [CCC.Other]
+  applicability: [tlp-clear]
+services:
+  my-service-1:
+    policy:
+      document: policy.yaml
+`,
+			wantId:            "storage-policy",
+			wantCatalogs:      []string{"CCC.ObjStor"},
+			wantApplicability: []string{"tlp-green", "tlp-amber"},
+		},
+		{
+			name: "embedded document",
+			config: `policy:
+  document:
+    metadata: {id: inline-policy}
+    scope: {in: {groups: [tlp-clear]}}
+    imports:
+      catalogs: [{reference-id: CCC.ObjStor}, {reference-id: CCC.KeyMgmt}]
+`,
+			wantId:            "inline-policy",
+			wantCatalogs:      []string{"CCC.ObjStor", "CCC.KeyMgmt"},
+			wantApplicability: []string{"tlp-clear"},
+		},
+		{
+			name:      "missing document",
+			config:    "policy:\n  document: missing.yaml\n",
+			wantError: "reading policy document",
+		},
+		{
+			name:      "not a policy",
+			config:    "policy:\n  document: guidance.yaml\n",
+			wantError: "policy document g is a ControlCatalog, not a Policy",
+		},
+		{
+			name: "catalog import without a reference",
+			config: `policy:
+  document:
+    metadata: {id: p}
+    imports: {catalogs: [{exclusions: [C01]}]}
+`,
+			wantError: "policy document p imports a catalog without a reference-id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigFile(filepath.Join(dir, "config.yml"))
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewBufferString(tt.config)); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			policy, err := loadPolicy("my-service-1")
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if policy.Document == nil || policy.Document.Metadata.Id != tt.wantId {
+				t.Errorf("expected policy document %s, got %+v", tt.wantId, policy.Document)
+			}
+			if strings.Join(policy.ControlCatalogs, ",") != strings.Join(tt.wantCatalogs, ",") {
+				t.Errorf("catalogs = %v, want %v", policy.ControlCatalogs, tt.wantCatalogs)
+			}
+			if strings.Join(policy.Applicability, ",") != strings.Join(tt.wantApplicability, ",") {
+				t.Errorf("applicability = %v, want %v", policy.Applicability, tt.wantApplicability)
+			}
+		})
+	}
+}
+
+func TestPolicy_Selects(t *testing.T) {
+	policy := Policy{Document: &gemara.Policy{
+		Metadata: gemara.Metadata{Id: "p"},
+		Imports: gemara.Imports{Catalogs: []gemara.CatalogImport{{
+			ReferenceId: "C",
+			Exclusions:  []string{"C.C02", "C.C03.TR02"},
+			AssessmentRequirementModifications: []gemara.AssessmentRequirementModifier{
+				{TargetId: "C.C01.TR02", ModificationType: gemara.ModRemove, ModificationRationale: "not used"},
+			},
+		}}},
+		Adherence: gemara.Adherence{AssessmentPlans: []gemara.AssessmentPlan{
+			{Id: "a", RequirementId: "C.C01.TR01"}, {Id: "b", RequirementId: "C.C03.TR01"}, {Id: "c", RequirementId: "D.C01.TR01"},
+		}},
+	}}
+	tests := []struct {
+		catalog, control, requirement string
+		wantReason                    string
+	}{
+		{"C", "C.C01", "C.C01.TR01", ""},
+		{"C", "C.C01", "C.C01.TR02", "removed by policy p: not used"},
+		{"C", "C.C02", "C.C02.TR01", "excluded by policy p"},
+		{"C", "C.C03", "C.C03.TR02", "excluded by policy p"},
+		{"C", "C.C04", "C.C04.TR01", "policy p has no assessment plan for this requirement"},
+		{"D", "C.C02", "D.C01.TR01", ""}, // exclusions belong to their own catalog import
+	}
+	for _, tt := range tests {
+		selected, reason := policy.Selects(tt.catalog, tt.control, tt.requirement)
+		if selected != (tt.wantReason == "") || reason != tt.wantReason {
+			t.Errorf("Selects(%s) = %v %q, want reason %q", tt.requirement, selected, reason, tt.wantReason)
+		}
+	}
+	if selected, _ := (Policy{}).Selects("C", "C.C02", "C.C02.TR01"); !selected {
+		t.Error("a policy without a document should select every requirement")
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// Waiver formally accepts the risk of a requirement that does not pass. While
+// it is active a matching assessment is reported as waived rather than failing
+// the run; once it expires the assessment fails again.
+type Waiver struct {
+	Catalog string `json:"catalog" yaml:"catalog"`
+	Control string `json:"control" yaml:"control"`
+	// Requirement may be empty to waive every requirement of the control.
+	Requirement string `json:"requirement,omitempty" yaml:"requirement,omitempty"`
+	// Service may be empty to waive the requirement for every service.
+	Service       string    `json:"service,omitempty" yaml:"service,omitempty"`
+	Justification string    `json:"justification" yaml:"justification"`
+	Approver      string    `json:"approver" yaml:"approver"`
+	Expires       time.Time `json:"expires" yaml:"expires"`
+}
+
+// rawWaiver is a waiver as written in config. Expires keeps its literal, which
+// CodecRegistry preserves for YAML files, so a date can be told apart from a
+// time at midnight wherever the waiver was set.
+type rawWaiver struct {
+	Catalog       string `mapstructure:"catalog"`
+	Control       string `mapstructure:"control"`
+	Requirement   string `mapstructure:"requirement"`
+	Service       string `mapstructure:"service"`
+	Justification string `mapstructure:"justification"`
+	Approver      string `mapstructure:"approver"`
+	Expires       string `mapstructure:"expires"`
+}
+
+// Matches reports whether the waiver covers the given requirement.
+func (w Waiver) Matches(catalog, control, requirement string) bool {
+	return w.Catalog == catalog && w.Control == control && (w.Requirement == "" || w.Requirement == requirement)
+}
+
+// Active reports whether the waiver has not yet expired at now.
+func (w Waiver) Active(now time.Time) bool {
+	return now.Before(w.Expires)
+}
+
+// loadWaivers collects the waivers that apply to serviceName from the
+// top-level "waivers" list, the service's own "waivers" list, and the YAML
+// file named by "waivers-file" (a document with a top-level "waivers" list).
+func loadWaivers(serviceName string) ([]Waiver, error) {
+	var raw []rawWaiver
+	if err := viper.UnmarshalKey("waivers", &raw, expiresHook); err != nil {
+		return nil, fmt.Errorf("reading waivers: %w", err)
+	}
+	var serviceRaw []rawWaiver
+	if err := viper.UnmarshalKey(fmt.Sprintf("services.%s.waivers", serviceName), &serviceRaw, expiresHook); err != nil {
+		return nil, fmt.Errorf("reading waivers for service %s: %w", serviceName, err)
+	}
+	for i := range serviceRaw {
+		if serviceRaw[i].Service == "" {
+			serviceRaw[i].Service = serviceName
+		}
+	}
+	raw = append(raw, serviceRaw...)
+
+	if file := viper.GetString("waivers-file"); file != "" {
+		fileConfig := viper.NewWithOptions(viper.WithCodecRegistry(CodecRegistry()))
+		fileConfig.SetConfigFile(file)
+		fileConfig.SetConfigType("yaml")
+		if err := fileConfig.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("reading waivers-file: %w", err)
+		}
+		var fileRaw []rawWaiver
+		if err := fileConfig.UnmarshalKey("waivers", &fileRaw, expiresHook); err != nil {
+			return nil, fmt.Errorf("reading waivers-file %s: %w", file, err)
+		}
+		raw = append(raw, fileRaw...)
+	}
+
+	var waivers []Waiver
+	for i, r := range raw {
+		if r.Service != "" && r.Service != serviceName {
+			continue
+		}
+		waiver, err := r.parse()
+		if err != nil {
+			return nil, fmt.Errorf("waiver %d (%s/%s): %w", i+1, r.Control, r.Requirement, err)
+		}
+		waivers = append(waivers, waiver)
+	}
+	return waivers, nil
+}
+
+// expiresHook decodes an expiry set in code as a time.Time, or read by a
+// codec that parses timestamps, as the instant it names.
+var expiresHook = viper.DecodeHook(mapstructure.DecodeHookFuncType(func(_, to reflect.Type, data any) (any, error) {
+	if at, ok := data.(time.Time); ok && to.Kind() == reflect.String {
+		return at.Format(time.RFC3339Nano), nil
+	}
+	return data, nil
+}))
+
+// parse validates a waiver. Every waiver must name who approved it, why, and
+// until when; a date without a time is valid through the end of that day (UTC).
+func (r rawWaiver) parse() (Waiver, error) {
+	w := Waiver{
+		Catalog:       strings.TrimSpace(r.Catalog),
+		Control:       strings.TrimSpace(r.Control),
+		Requirement:   strings.TrimSpace(r.Requirement),
+		Service:       strings.TrimSpace(r.Service),
+		Justification: strings.TrimSpace(r.Justification),
+		Approver:      strings.TrimSpace(r.Approver),
+	}
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"catalog", w.Catalog}, {"control", w.Control}, {"justification", w.Justification}, {"approver", w.Approver},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	expires := strings.TrimSpace(r.Expires)
+	if expires == "" {
+		missing = append(missing, "expires")
+	}
+	if len(missing) > 0 {
+		return w, fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	if day, err := time.Parse(time.DateOnly, expires); err == nil {
+		w.Expires = day.AddDate(0, 0, 1)
+	} else if at, err := time.Parse(time.RFC3339, expires); err == nil {
+		w.Expires = at
+	} else {
+		return w, fmt.Errorf("expires must be a date (2006-01-02) or RFC 3339 time, got %q", expires)
+	}
+	return w, nil
+}
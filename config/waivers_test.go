@@ -0,0 +1,178 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadWaivers(t *testing.T) {
+	waiversFile := filepath.Join(t.TempDir(), "waivers.yaml")
+	if err := os.WriteFile(waiversFile, []byte(`waivers:
+  - catalog: CCC.ObjStor
+    control: CCC.ObjStor.C03
+    justification: from file
+    approver: ciso
+    expires: "2030-01-01T12:00:00Z"
+`), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	waiversDateFile := filepath.Join(t.TempDir(), "waivers-date.yaml")
+	if err := os.WriteFile(waiversDateFile, []byte(`waivers:
+  - {catalog: C, control: C.C05, justification: j, approver: a, expires: 2030-05-31}
+`), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		config    string
+		merge     string         // config merged over the file, as a second config is
+		set       map[string]any // keys set in code, as flags and env are
+		want      []string       // control/requirement@expires for each waiver
+		wantError string
+	}{
+		{name: "none configured"},
+		{
+			name: "top level date is valid through that day",
+			config: `waivers:
+  - {catalog: CCC.ObjStor, control: CCC.ObjStor.C01, requirement: CCC.ObjStor.C01.TR01, justification: j, approver: a, expires: 2030-06-30}
+`,
+			want: []string{"CCC.ObjStor.C01/CCC.ObjStor.C01.TR01@2030-07-01T00:00:00Z"},
+		},
+		{
+			name: "a time at midnight is not extended",
+			config: `waivers:
+  - {catalog: C, control: C.C01, justification: j, approver: a, expires: 2026-01-01T00:00:00Z}
+services:
+  My-Service-1:
+    waivers:
+      - {catalog: C, control: C.C02, justification: j, approver: a, expires: 2026-01-01}
+`,
+			want: []string{"C.C01/@2026-01-01T00:00:00Z", "C.C02/@2026-01-02T00:00:00Z"},
+		},
+		{
+			name: "waivers set outside a config file keep their literal",
+			set: map[string]any{
+				"waivers": []map[string]any{
+					{"catalog": "C", "control": "C.C01", "justification": "j", "approver": "a", "expires": "2026-01-01"},
+					{"catalog": "C", "control": "C.C02", "justification": "j", "approver": "a", "expires": time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			want: []string{"C.C01/@2026-01-02T00:00:00Z", "C.C02/@2026-01-01T00:00:00Z"},
+		},
+		{
+			name: "service waivers merged from another file keep their literal",
+			config: `services:
+  my-service-1:
+    waivers:
+      - {catalog: C, control: C.C01, justification: j, approver: a, expires: 2026-01-01T00:00:00Z}
+`,
+			merge: `services:
+  my-service-1:
+    waivers:
+      - {catalog: C, control: C.C02, justification: j, approver: a, expires: 2026-01-01}
+`,
+			want: []string{"C.C02/@2026-01-02T00:00:00Z"},
+		},
+		{
+			name:   "date in a waivers file is valid through that day",
+			config: "waivers-file: " + waiversDateFile + "\n",
+			want:   []string{"C.C05/@2030-06-01T00:00:00Z"},
+		},
+		{
+			name: "service scoping",
+			config: `waivers:
+  - {catalog: C, control: C.C01, service: other-service, justification: j, approver: a, expires: "2030-06-30"}
+  - {catalog: C, control: C.C02, service: my-service-1, justification: j, approver: a, expires: "2030-06-30"}
+services:
+  my-service-1:
+    waivers:
+      - {catalog: C, control: C.C04, justification: j, approver: a, expires: "2030-06-30"}
+`,
+			want: []string{"C.C02/@2030-07-01T00:00:00Z", "C.C04/@2030-07-01T00:00:00Z"},
+		},
+		{
+			name:   "separate waivers file",
+			config: "waivers-file: " + waiversFile + "\n",
+			want:   []string{"CCC.ObjStor.C03/@2030-01-01T12:00:00Z"},
+		},
+		{
+			name: "justification and approver are required",
+			config: `waivers:
+  - {catalog: C, control: C.C01, expires: "2030-06-30"}
+`,
+			wantError: "waiver 1 (C.C01/): missing justification, approver",
+		},
+		{
+			name: "bad expiry",
+			config: `waivers:
+  - {catalog: C, control: C.C01, justification: j, approver: a, expires: next year}
+`,
+			wantError: `expires must be a date (2006-01-02) or RFC 3339 time, got "next year"`,
+		},
+		{
+			name:      "missing waivers file",
+			config:    "waivers-file: " + filepath.Join(t.TempDir(), "missing.yaml") + "\n",
+			wantError: "reading waivers-file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a config file, read as plugins read it
+			configFile := filepath.Join(t.TempDir(), "config.yml")
+			if err := os.WriteFile(configFile, []byte(tt.config), 0o640); err != nil {
+				t.Fatal(err)
+			}
+			viper.Reset()
+			viper.SetOptions(viper.WithCodecRegistry(CodecRegistry()))
+			viper.SetConfigFile(configFile)
+			if err := viper.ReadInConfig(); err != nil {
+				t.Fatalf("error reading config: %v", err)
+			}
+			if err := viper.MergeConfig(strings.NewReader(tt.merge)); err != nil {
+				t.Fatalf("error merging config: %v", err)
+			}
+			for key, value := range tt.set {
+				viper.Set(key, value)
+			}
+			waivers, err := loadWaivers("my-service-1")
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, w := range waivers {
+				got = append(got, w.Control+"/"+w.Requirement+"@"+w.Expires.UTC().Format(time.RFC3339))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaiver_MatchesAndActive(t *testing.T) {
+	expires := time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC)
+	w := Waiver{Catalog: "C", Control: "C.C01", Expires: expires}
+	if !w.Matches("C", "C.C01", "C.C01.TR02") {
+		t.Error("a waiver without a requirement should cover every requirement of its control")
+	}
+	w.Requirement = "C.C01.TR01"
+	if w.Matches("C", "C.C01", "C.C01.TR02") || w.Matches("D", "C.C01", "C.C01.TR01") {
+		t.Error("a waiver should only match its own catalog and requirement")
+	}
+	if !w.Active(expires.Add(-time.Second)) || w.Active(expires) {
+		t.Error("a waiver should be active strictly before it expires")
+	}
+}
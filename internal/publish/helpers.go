@@ -14,43 +14,62 @@ import (
 	"github.com/privateerproj/privateer-sdk/pluginkit"
 )
 
-// manifestExecTimeout bounds running the plugin's publish-manifest subcommand.
-// It is the publisher's own freshly-built binary, so this only guards against a
-// hung process, not a hostile one.
+// manifestExecTimeout bounds running one of the plugin's metadata subcommands
+// (publish-manifest, coverage). It is the publisher's own freshly-built binary,
+// so this only guards against a hung process, not a hostile one.
 const manifestExecTimeout = 30 * time.Second
 
 // execPublishManifest selects the host-platform binary from the build and runs
 // its publish-manifest subcommand, decoding the JSON stdout. The binary is the
 // publisher's own freshly-built plugin, so running it to ask "what do you
 // publish as?" is safe (unlike install time, where foreign bytes are never
-// executed). stderr is captured only to enrich an error — ReadConfig's
-// "[ERROR]" log lands there and is not the manifest.
+// executed).
 func execPublishManifest(ctx context.Context, bins []oci.PlatformBinary) (pluginkit.PublishManifest, error) {
-	var zero pluginkit.PublishManifest
+	var m pluginkit.PublishManifest
+	if err := execPluginJSON(ctx, bins, &m, pluginkit.PublishManifestCommand); err != nil {
+		return pluginkit.PublishManifest{}, err
+	}
+	return m, nil
+}
+
+// execCoverage runs the host-platform binary's coverage subcommand and decodes
+// its JSON report. The gate itself is applied by the caller, so a plugin's own
+// coverage flags never decide whether it may be published.
+func execCoverage(ctx context.Context, bins []oci.PlatformBinary) (pluginkit.CoverageReport, error) {
+	var report pluginkit.CoverageReport
+	if err := execPluginJSON(ctx, bins, &report, pluginkit.CoverageCommand, "--json"); err != nil {
+		return pluginkit.CoverageReport{}, err
+	}
+	return report, nil
+}
+
+// execPluginJSON runs a subcommand of the host-platform binary and decodes its
+// JSON stdout into out. stderr is captured only to enrich an error —
+// ReadConfig's "[ERROR]" log lands there and is not the output.
+func execPluginJSON(ctx context.Context, bins []oci.PlatformBinary, out any, args ...string) error {
 	host, err := oci.HostPlatformBinary(bins)
 	if err != nil {
-		return zero, fmt.Errorf("selecting a host binary to run: %w", err)
+		return fmt.Errorf("selecting a host binary to run: %w", err)
 	}
 	hostBinaryPath := host.Path
 
 	ctx, cancel := context.WithTimeout(ctx, manifestExecTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, hostBinaryPath, pluginkit.PublishManifestCommand)
+	cmd := exec.CommandContext(ctx, hostBinaryPath, args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
 		if detail := strings.TrimSpace(stderr.String()); detail != "" {
-			return zero, fmt.Errorf("%s %s: %w: %s", filepath.Base(hostBinaryPath), pluginkit.PublishManifestCommand, err, detail)
+			return fmt.Errorf("%s %s: %w: %s", filepath.Base(hostBinaryPath), args[0], err, detail)
 		}
-		return zero, fmt.Errorf("%s %s: %w", filepath.Base(hostBinaryPath), pluginkit.PublishManifestCommand, err)
+		return fmt.Errorf("%s %s: %w", filepath.Base(hostBinaryPath), args[0], err)
 	}
-	var m pluginkit.PublishManifest
-	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &m); err != nil {
-		return zero, fmt.Errorf("decoding publish manifest JSON: %w", err)
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), out); err != nil {
+		return fmt.Errorf("decoding %s JSON: %w", args[0], err)
 	}
-	return m, nil
+	return nil
 }
 
 // parseRegistryOverride splits a --registry value that MUST carry a scheme into
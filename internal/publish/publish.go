@@ -28,12 +28,20 @@ type Params struct {
 	Registry string
 	// NoSync is a push-only smoke mode: skip signing AND /sync.
 	NoSync bool
+	// MinCoverage, when above zero, refuses to publish a plugin whose step
+	// coverage of any evaluated catalog is below this percentage.
+	MinCoverage float64
+	// FailOnOrphaned refuses to publish a plugin that registers steps for
+	// ids its catalogs do not define.
+	FailOnOrphaned bool
 
 	// resolveManifest overrides how the plugin's publish manifest is obtained
 	// from the resolved build. Nil uses execPublishManifest (select the host
 	// binary and run its publish-manifest subcommand); tests inject a stub so
 	// they need no real plugin binary for the host platform.
 	resolveManifest func(ctx context.Context, bins []oci.PlatformBinary) (pluginkit.PublishManifest, error)
+	// resolveCoverage likewise overrides execCoverage.
+	resolveCoverage func(ctx context.Context, bins []oci.PlatformBinary) (pluginkit.CoverageReport, error)
 }
 
 // Publish runs the complete producer flow. The plugin coordinate and the
@@ -80,6 +88,23 @@ func Publish(ctx context.Context, w io.Writer, p Params) error {
 	}
 	_, _ = fmt.Fprintf(w, "Loaded %s version %s (%d platforms)\n", coordinate, version, len(bins))
 
+	// Coverage gate: only when asked for, so plugins built against an SDK
+	// without the coverage subcommand still publish.
+	if p.MinCoverage > 0 || p.FailOnOrphaned {
+		resolveCoverage := p.resolveCoverage
+		if resolveCoverage == nil {
+			resolveCoverage = execCoverage
+		}
+		report, err := resolveCoverage(ctx, bins)
+		if err != nil {
+			return fmt.Errorf("reading step coverage from the plugin: %w", err)
+		}
+		report.Render(w)
+		if err := report.Check(p.MinCoverage, p.FailOnOrphaned); err != nil {
+			return fmt.Errorf("plugin is not publishable: %w", err)
+		}
+	}
+
 	// Convert the plugin-declared evaluates (the public pluginkit type) into the
 	// shared protocol type at this boundary. Keeping pluginkit.EvaluatesDeclaration
 	// as our own public struct insulates community plugins from grc-store-protocol's
@@ -361,3 +361,47 @@ func mockHub(t *testing.T, tokenActions []string, pushHit *bool) *httptest.Serve
 	srv = httptest.NewServer(mux)
 	return srv
 }
+
+func TestPublish_CoverageGateFailsBeforePush(t *testing.T) {
+	// A plugin below the requested coverage fails before discovery/push. No
+	// PVTR_HUB_URL is set, so reaching discovery would be a different error.
+	report := pluginkit.CoverageReport{Catalogs: []pluginkit.CatalogCoverage{{
+		CatalogId: "example", Requirements: 4, Uncovered: []string{"R2", "R3"}, Orphaned: []string{"R9"}, Percent: 50,
+	}}}
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr string
+	}{
+		{name: "below threshold", params: Params{MinCoverage: 80}, wantErr: "below the 80.0% minimum"},
+		{name: "orphaned steps", params: Params{FailOnOrphaned: true}, wantErr: "steps for ids not in the catalog: R9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.params
+			p.DistDir = writeMinimalDist(t)
+			p.resolveManifest = stubManifest(acmeHelloManifest())
+			p.resolveCoverage = func(context.Context, []oci.PlatformBinary) (pluginkit.CoverageReport, error) { return report, nil }
+			err := Publish(context.Background(), io.Discard, p)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected the coverage gate to fail with %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPublish_CoverageNotReadWithoutGate(t *testing.T) {
+	// Without a gate the coverage subcommand is never run, so plugins built
+	// against an SDK that lacks it still publish.
+	err := Publish(context.Background(), io.Discard, Params{
+		DistDir:         writeMinimalDist(t),
+		resolveManifest: stubManifest(pluginkit.PublishManifest{}),
+		resolveCoverage: func(context.Context, []oci.PlatformBinary) (pluginkit.CoverageReport, error) {
+			t.Fatal("coverage read without a gate")
+			return pluginkit.CoverageReport{}, nil
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "coordinate") {
+		t.Errorf("expected the coordinate error, got %v", err)
+	}
+}
@@ -44,8 +44,19 @@ type LoaderTiming struct {
 	Scope      string `json:"scope" yaml:"scope"`
 	Func       string `json:"func" yaml:"func"`
 	DurationNs int64  `json:"duration-ns" yaml:"duration-ns"`
+	// Source is empty when the loader ran, or says where the payload came
+	// from instead: LoaderSourceReplay or LoaderSourceCache.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 }
 
+// Loader payload sources other than a live loader call, for LoaderTiming.Source.
+const (
+	// LoaderSourceReplay marks a payload decoded from a replayed fixture.
+	LoaderSourceReplay = "replay"
+	// LoaderSourceCache marks a payload cache hit.
+	LoaderSourceCache = "cache"
+)
+
 // SuiteTiming times one evaluation suite and its executed steps.
 type SuiteTiming struct {
 	CatalogId  string       `json:"catalog-id" yaml:"catalog-id"`
@@ -60,7 +71,10 @@ type StepTiming struct {
 	StepIndex     int    `json:"step-index" yaml:"step-index"`
 	Step          string `json:"step" yaml:"step"`
 	Result        string `json:"result" yaml:"result"`
-	DurationNs    int64  `json:"duration-ns" yaml:"duration-ns"`
+	// Attempts is how many times the step ran under its RetryPolicy; the
+	// duration covers every attempt and the waits between them.
+	Attempts   int   `json:"attempts" yaml:"attempts"`
+	DurationNs int64 `json:"duration-ns" yaml:"duration-ns"`
 }
 
 // funcName resolves a function value's symbol name, as gemara names steps.
@@ -77,7 +91,7 @@ func funcName(fn any) string {
 }
 
 // recordLoader appends a loader timing when benchmark mode is active.
-func (v *EvaluationOrchestrator) recordLoader(scope string, loader DataLoader, d time.Duration) {
+func (v *EvaluationOrchestrator) recordLoader(scope string, loader DataLoader, d time.Duration, source string) {
 	if v.benchmark == nil {
 		return
 	}
@@ -85,6 +99,7 @@ func (v *EvaluationOrchestrator) recordLoader(scope string, loader DataLoader, d
 		Scope:      scope,
 		Func:       funcName(loader),
 		DurationNs: d.Nanoseconds(),
+		Source:     source,
 	})
 }
 
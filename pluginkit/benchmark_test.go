@@ -234,7 +234,7 @@ func TestBenchmark_ResultsDocumentKeepsRealStepNames(t *testing.T) {
 	cfg.Policy.ControlCatalogs = []string{"CCC.ObjStor"}
 	cfg.Write = true
 	cfg.WriteDirectory = tmpDir
-	cfg.Output = "json"
+	cfg.Output = []string{"json"}
 	cfg.Benchmark = true
 
 	steps := map[string][]gemara.AssessmentStep{
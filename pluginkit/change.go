@@ -3,6 +3,8 @@ package pluginkit
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 )
 
 // ApplyFunc is a prepared function to apply a change.
@@ -19,6 +21,11 @@ type ChangeManager struct {
 	Allowed bool `yaml:"allowed,omitempty"`
 	// CorruptedState is true if any change has failed to apply or revert, indicating that the system may be in a bad state.
 	CorruptedState bool `yaml:"bad-state,omitempty"`
+
+	journal      *changeJournal // journal records applied changes on disk; nil outside an invasive run
+	catalogId    string         // catalogId identifies the change manager in the journal
+	applied      []string       // applied names the changes applied and not yet reverted, in application order
+	revertPolicy RevertPolicy   // revertPolicy applies to every change without its own; see SetRevertPolicy
 }
 
 // Change is a struct that contains the data and functions associated with a single change to a target resource.
@@ -41,6 +48,12 @@ type Change struct {
 	Error error `yaml:"error,omitempty"`
 	// CorruptedState is true if something went wrong during apply or revert, indicating that the system may be in a bad state
 	CorruptedState bool `yaml:"bad-state,omitempty"`
+	// targetType is the type journaled target objects are decoded into; see SetTargetType
+	targetType reflect.Type
+	// revertPolicy overrides the change manager's revert policy; see SetRevertPolicy
+	revertPolicy *RevertPolicy
+	// revertAttempts is the number of attempts the last revert took
+	revertAttempts int
 }
 
 // Allow marks changes as allowed to be applied.
@@ -64,6 +77,9 @@ func (c *Change) AddFunctions(applyFunc ApplyFunc, revertFunc RevertFunc) {
 
 // Apply executes the prepared function for the change.
 // It will not apply the change if it is not allowed, or if it has already been applied and not reverted.
+// During an invasive run the applied change is journaled to disk before Apply returns, so it can be
+// reverted by the plugin's revert command if the process dies; a change that cannot be journaled is
+// reverted straight away and reported as not applied.
 func (cm *ChangeManager) Apply(changeName string, targetName string, changeInput any) (success bool, target any) {
 	if !cm.Allowed {
 		return false, nil
@@ -72,7 +88,17 @@ func (cm *ChangeManager) Apply(changeName string, targetName string, changeInput
 	if !exists {
 		return false, nil
 	}
+	alreadyApplied := change.Applied && !change.Reverted
 	success, target = change.apply(targetName, changeInput)
+	if success && !alreadyApplied {
+		if err := cm.record(changeName, change); err != nil {
+			change.revert(change.TargetObject, cm.revertPolicyFor(change))
+			if change.Error == nil {
+				change.Error = fmt.Errorf("change was reverted because it could not be journaled: %w", err)
+			}
+			success = false
+		}
+	}
 	if change.CorruptedState {
 		cm.CorruptedState = true
 	}
@@ -85,18 +111,49 @@ func (cm *ChangeManager) Revert(changeName string) {
 	if !exists {
 		return
 	}
-	change.revert(change.TargetObject)
-	if change.CorruptedState {
-		cm.CorruptedState = true
+	cm.revert(changeName, change)
+}
+
+// RevertAll reverts the changes that are applied and not yet reverted, in the
+// reverse of the order they were applied, so a change is undone before those
+// it may depend on. Each revert follows the revert policy. The returned report
+// covers every change of the manager.
+func (cm *ChangeManager) RevertAll() []ChangeRevert {
+	order := cm.revertOrder()
+	for _, name := range order {
+		cm.revert(name, cm.Changes[name])
+	}
+	return cm.revertReport(order)
+}
+
+// record notes a change that was just applied, journaling it during an
+// invasive run.
+func (cm *ChangeManager) record(changeName string, change *Change) error {
+	if cm.journal != nil {
+		if err := cm.journal.record(cm.catalogId, changeName, change); err != nil {
+			return err
+		}
 	}
+	cm.applied = append(cm.applied, changeName)
+	return nil
 }
 
-// RevertAll reverts all changes managed by the change manager.
-func (cm *ChangeManager) RevertAll() {
-	for _, change := range cm.Changes {
-		change.revert(change.TargetObject)
-		if change.CorruptedState {
-			cm.CorruptedState = true
+// revert reverts one change, and removes it from the journal once reverted.
+func (cm *ChangeManager) revert(changeName string, change *Change) {
+	change.revert(change.TargetObject, cm.revertPolicyFor(change))
+	if change.CorruptedState {
+		cm.CorruptedState = true
+	}
+	if !change.Reverted {
+		return
+	}
+	if i := slices.Index(cm.applied, changeName); i >= 0 {
+		cm.applied = slices.Delete(cm.applied, i, i+1)
+	}
+	if cm.journal != nil {
+		if err := cm.journal.remove(cm.catalogId, changeName); err != nil {
+			// the change is reverted; a stale entry only makes the revert command revert it again
+			change.Error = fmt.Errorf("change was reverted but is still journaled: %w", err)
 		}
 	}
 }
@@ -129,9 +186,9 @@ func (c *Change) apply(targetName string, changeInput any) (success bool, target
 	return true, c.TargetObject
 }
 
-// revert reverts the change by executing the revert function.
+// revert reverts the change by executing the revert function under policy.
 // It does nothing if it has not been applied.
-func (c *Change) revert(data interface{}) {
+func (c *Change) revert(data interface{}, policy RevertPolicy) {
 	if !c.Applied {
 		return
 	}
@@ -140,7 +197,7 @@ func (c *Change) revert(data interface{}) {
 		c.Error = err
 		return
 	}
-	err = c.revertFunc(data)
+	c.revertAttempts, err = policy.run(c.revertFunc, data)
 	if err != nil {
 		c.Error = err
 		c.CorruptedState = true
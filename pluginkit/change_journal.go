@@ -0,0 +1,246 @@
+package pluginkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/privateerproj/privateer-sdk/utils"
+)
+
+// RevertCommand is the plugin subcommand that reverts the changes a run left
+// applied, as recorded in the service's change journal. command.NewPluginCommands
+// wires it onto every plugin.
+const RevertCommand = "revert"
+
+// changeJournal is the on-disk record of the invasive changes applied to a
+// service and not yet reverted. A change is journaled as soon as it is
+// applied and leaves the journal once reverted, so after a run that finished
+// the journal is empty and its file removed; after one that crashed it holds
+// exactly what RevertJournal must undo.
+type changeJournal struct {
+	PluginName  string         `json:"plugin-name"`
+	ServiceName string         `json:"service-name"`
+	Entries     []JournalEntry `json:"entries"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// JournalEntry is one applied change in the change journal.
+type JournalEntry struct {
+	CatalogId  string `json:"catalog-id"`
+	Change     string `json:"change"`
+	TargetName string `json:"target-name"`
+	// TargetObject is the change's TargetObject as JSON, handed back to its
+	// revert function when the change is reverted from the journal.
+	TargetObject json.RawMessage `json:"target-object,omitempty"`
+	AppliedAt    string          `json:"applied-at"`
+}
+
+// changeJournalPath is the service's journal file in the configured journal
+// directory. It is named for the plugin too, since services of different
+// plugins may share a name across config files.
+func (v *EvaluationOrchestrator) changeJournalPath() string {
+	return filepath.Join(v.config.ChangeJournalDirectory, fmt.Sprintf("%s_%s.json", v.PluginName, v.config.ServiceName))
+}
+
+// readChangeJournal loads the service's change journal, which is empty when
+// no file exists.
+func (v *EvaluationOrchestrator) readChangeJournal() (*changeJournal, error) {
+	journal := &changeJournal{PluginName: v.PluginName, ServiceName: v.config.ServiceName, path: v.changeJournalPath()}
+	data, err := os.ReadFile(journal.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading change journal: %w", err)
+	}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("decoding change journal %s: %w", journal.path, err)
+	}
+	return journal, nil
+}
+
+// openChangeJournal returns the journal for an invasive run, or nil when no
+// change can be applied. Entries left by an earlier run are kept, so they can
+// still be reverted after this one.
+func (v *EvaluationOrchestrator) openChangeJournal() (*changeJournal, error) {
+	if !v.config.Invasive || len(v.changeManagers) == 0 {
+		return nil, nil
+	}
+	journal, err := v.readChangeJournal()
+	if err != nil {
+		return nil, err
+	}
+	if len(journal.Entries) > 0 {
+		v.config.Logger.Warn(fmt.Sprintf("changes applied by an earlier run were never reverted; run the plugin's %s command to revert them", RevertCommand),
+			"changes", len(journal.Entries), "journal", journal.path)
+	}
+	return journal, nil
+}
+
+// record journals a change that was just applied.
+func (j *changeJournal) record(catalogId, changeName string, change *Change) error {
+	target, err := json.Marshal(change.TargetObject)
+	if err != nil {
+		return fmt.Errorf("encoding the target object of %s: %w", changeName, err)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Entries = append(j.Entries, JournalEntry{
+		CatalogId:    catalogId,
+		Change:       changeName,
+		TargetName:   change.TargetName,
+		TargetObject: target,
+		AppliedAt:    time.Now().UTC().Format(time.RFC3339),
+	})
+	return j.save()
+}
+
+// remove drops the latest entry of a change that was just reverted.
+func (j *changeJournal) remove(catalogId, changeName string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		if j.Entries[i].CatalogId == catalogId && j.Entries[i].Change == changeName {
+			j.Entries = slices.Delete(j.Entries, i, i+1)
+			return j.save()
+		}
+	}
+	return nil
+}
+
+// save replaces the journal file through a synced temporary file, so a crash
+// while saving leaves either the old journal or the new one. An empty journal
+// removes the file.
+func (j *changeJournal) save() error {
+	if len(j.Entries) == 0 {
+		if err := os.Remove(j.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing change journal: %w", err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding change journal: %w", err)
+	}
+	dir := filepath.Dir(j.path)
+	if err := os.MkdirAll(dir, utils.DirPermissions); err != nil {
+		return fmt.Errorf("writing change journal: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".change-journal-*")
+	if err != nil {
+		return fmt.Errorf("writing change journal: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), j.path)
+	}
+	if err != nil {
+		return fmt.Errorf("writing change journal: %w", err)
+	}
+	return nil
+}
+
+// RevertJournal reverts the changes the service's change journal holds,
+// newest first: changes an earlier run applied and never reverted, usually
+// because the plugin process died. Each change is looked up among those
+// registered with AddChangeManager and its revert function is given the
+// journaled target object, under the change's revert policy. Reverted changes leave the journal and are
+// returned; a change that cannot be reverted stays for another attempt and is
+// named in the error.
+func (v *EvaluationOrchestrator) RevertJournal() ([]JournalEntry, error) {
+	v.setupConfig()
+	if v.config.Error != nil {
+		return nil, BAD_CONFIG(v.config.Error, "rvj10")
+	}
+	if v.PluginName == "" || v.config.ServiceName == "" {
+		return nil, EVALUATION_ORCHESTRATOR_NAMES_NOT_SET(v.config.ServiceName, v.PluginName, "rvj20")
+	}
+	journal, err := v.readChangeJournal()
+	if err != nil {
+		return nil, CHANGE_JOURNAL_FAILED(err, "rvj30")
+	}
+
+	var reverted []JournalEntry
+	var failures []string
+	for i := len(journal.Entries) - 1; i >= 0; i-- {
+		entry := journal.Entries[i]
+		if err := v.revertJournalEntry(entry); err != nil {
+			v.config.Logger.Error("journaled change was not reverted", "change", entry.Change, "target", entry.TargetName, "error", err)
+			failures = append(failures, fmt.Sprintf("%s on %s: %s", entry.Change, entry.TargetName, err))
+			continue
+		}
+		journal.Entries = slices.Delete(journal.Entries, i, i+1)
+		if err := journal.save(); err != nil {
+			return reverted, CHANGE_JOURNAL_FAILED(err, "rvj40")
+		}
+		reverted = append(reverted, entry)
+	}
+	if len(failures) > 0 {
+		return reverted, REVERT_FAILED(strings.Join(failures, "; "), "rvj50")
+	}
+	return reverted, nil
+}
+
+func (v *EvaluationOrchestrator) revertJournalEntry(entry JournalEntry) error {
+	cm := v.changeManagers[entry.CatalogId]
+	if cm == nil {
+		return fmt.Errorf("no change manager is registered for catalog %s", entry.CatalogId)
+	}
+	change, ok := cm.Changes[entry.Change]
+	if !ok {
+		return fmt.Errorf("the change is not registered with the change manager of %s", entry.CatalogId)
+	}
+	if change.revertFunc == nil {
+		return fmt.Errorf("the change has no revert function")
+	}
+	target, err := change.decodeTarget(entry.TargetObject)
+	if err != nil {
+		return err
+	}
+	_, err = cm.revertPolicyFor(change).run(change.revertFunc, target)
+	return err
+}
+
+// SetTargetType declares the type of the change's TargetObject by example,
+// so a target object read back from the change journal is decoded into it
+// before it reaches the revert function. Without it the object is decoded as
+// untyped JSON: maps, slices, strings, float64s and bools.
+func (c *Change) SetTargetType(prototype any) {
+	c.targetType = reflect.TypeOf(prototype)
+}
+
+func (c *Change) decodeTarget(raw json.RawMessage) (any, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	if c.targetType == nil {
+		var target any
+		if err := json.Unmarshal(raw, &target); err != nil {
+			return nil, fmt.Errorf("decoding the journaled target object: %w", err)
+		}
+		return target, nil
+	}
+	target := reflect.New(c.targetType)
+	if err := json.Unmarshal(raw, target.Interface()); err != nil {
+		return nil, fmt.Errorf("decoding the journaled target object into %s: %w", c.targetType, err)
+	}
+	return target.Elem().Interface(), nil
+}
@@ -0,0 +1,189 @@
+package pluginkit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// bucketPolicy is a serialisable target object, as a plugin's apply function
+// might return it.
+type bucketPolicy struct {
+	Bucket string `json:"bucket"`
+	Public bool   `json:"public"`
+}
+
+// journalOrchestrator has one change manager for CCC.ObjStor and keeps its
+// change journal in a temporary directory.
+func journalOrchestrator(t *testing.T, changes map[string]Change) *EvaluationOrchestrator {
+	t.Helper()
+	cfg := setBasicConfig()
+	cfg.Invasive = true
+	cfg.ChangeJournalDirectory = t.TempDir()
+	v := &EvaluationOrchestrator{PluginName: "test-plugin", config: cfg}
+	cm := &ChangeManager{}
+	for name, change := range changes {
+		cm.AddChange(name, change)
+	}
+	v.AddChangeManager("CCC.ObjStor", cm)
+	return v
+}
+
+func readJournal(t *testing.T, v *EvaluationOrchestrator) []JournalEntry {
+	t.Helper()
+	journal, err := v.readChangeJournal()
+	if err != nil {
+		t.Fatalf("reading journal: %v", err)
+	}
+	return journal.Entries
+}
+
+func TestChangeManager_Journal(t *testing.T) {
+	change := pendingChange()
+	change.AddFunctions(func(any) (any, error) { return bucketPolicy{Bucket: "logs", Public: true}, nil }, goodRevertFunc)
+	v := journalOrchestrator(t, map[string]Change{"make-public": change})
+	journal, err := v.openChangeJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cm := v.changeManagers["CCC.ObjStor"]
+	cm.journal, cm.catalogId = journal, "CCC.ObjStor"
+	cm.Allow()
+
+	for range 2 { // applying an applied change again must not journal it twice
+		if success, _ := cm.Apply("make-public", "logs", nil); !success {
+			t.Fatalf("Apply failed: %v", cm.Changes["make-public"].Error)
+		}
+	}
+	entries := readJournal(t, v)
+	if len(entries) != 1 {
+		t.Fatalf("expected one journal entry, got %+v", entries)
+	}
+	if entries[0].CatalogId != "CCC.ObjStor" || entries[0].Change != "make-public" || entries[0].TargetName != "logs" {
+		t.Errorf("unexpected journal entry %+v", entries[0])
+	}
+	var target bucketPolicy
+	if err := json.Unmarshal(entries[0].TargetObject, &target); err != nil || target != (bucketPolicy{Bucket: "logs", Public: true}) {
+		t.Errorf("expected the target object to be journaled, got %s", entries[0].TargetObject)
+	}
+
+	cm.Revert("make-public")
+	if _, err := os.Stat(v.changeJournalPath()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the journal file to be removed once every change is reverted, got %v", err)
+	}
+}
+
+func TestChangeManager_Journal_UnjournaledChangeIsReverted(t *testing.T) {
+	reverted := false
+	change := pendingChange()
+	change.AddFunctions(func(any) (any, error) { return make(chan int), nil }, func(any) error { reverted = true; return nil })
+	v := journalOrchestrator(t, map[string]Change{"unserialisable": change})
+	journal, _ := v.openChangeJournal()
+	cm := v.changeManagers["CCC.ObjStor"]
+	cm.journal, cm.catalogId = journal, "CCC.ObjStor"
+	cm.Allow()
+
+	if success, _ := cm.Apply("unserialisable", "target", nil); success {
+		t.Error("expected Apply to fail when the change cannot be journaled")
+	}
+	if !reverted {
+		t.Error("expected the unjournaled change to be reverted straight away")
+	}
+	if err := cm.Changes["unserialisable"].Error; err == nil || !strings.Contains(err.Error(), "could not be journaled") {
+		t.Errorf("expected the change error to explain the journal failure, got %v", err)
+	}
+	if cm.CorruptedState {
+		t.Error("a change that was reverted must not mark the state corrupted")
+	}
+}
+
+func TestEvaluationOrchestrator_RevertJournal(t *testing.T) {
+	tests := []struct {
+		name          string
+		failing       string // change whose revert fails
+		wantOrder     []string
+		wantRemaining []string
+		wantErr       string
+	}{
+		{
+			name:      "newest first",
+			wantOrder: []string{"second", "first"},
+		},
+		{
+			name:          "failed revert stays journaled",
+			failing:       "second",
+			wantOrder:     []string{"second", "first"},
+			wantRemaining: []string{"second"},
+			wantErr:       "second on bucket-second: revert error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order []string
+			var targets []any
+			changes := make(map[string]Change)
+			for _, name := range []string{"first", "second"} {
+				change := pendingChange()
+				change.SetTargetType(bucketPolicy{})
+				change.AddFunctions(goodApplyFunc, func(target any) error {
+					order = append(order, name)
+					targets = append(targets, target)
+					if name == tt.failing {
+						return errors.New("revert error")
+					}
+					return nil
+				})
+				changes[name] = change
+			}
+			v := journalOrchestrator(t, changes)
+
+			// the journal a crashed run leaves behind
+			journal, _ := v.readChangeJournal()
+			for _, name := range []string{"first", "second"} {
+				target, _ := json.Marshal(bucketPolicy{Bucket: name})
+				journal.Entries = append(journal.Entries, JournalEntry{CatalogId: "CCC.ObjStor", Change: name, TargetName: "bucket-" + name, TargetObject: target})
+			}
+			if err := journal.save(); err != nil {
+				t.Fatal(err)
+			}
+
+			reverted, err := v.RevertJournal()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("RevertJournal failed: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr) || !errors.Is(err, ErrRuntime)) {
+				t.Errorf("error = %v, want a runtime error containing %q", err, tt.wantErr)
+			}
+			if strings.Join(order, ",") != strings.Join(tt.wantOrder, ",") {
+				t.Errorf("reverted in order %v, want %v", order, tt.wantOrder)
+			}
+			if policy, ok := targets[0].(bucketPolicy); !ok || policy.Bucket != "second" {
+				t.Errorf("expected the journaled target decoded as a bucketPolicy, got %#v", targets[0])
+			}
+			if len(reverted) != len(tt.wantOrder)-len(tt.wantRemaining) {
+				t.Errorf("expected %d reverted entries, got %+v", len(tt.wantOrder)-len(tt.wantRemaining), reverted)
+			}
+
+			var remaining []string
+			for _, entry := range readJournal(t, v) {
+				remaining = append(remaining, entry.Change)
+			}
+			if strings.Join(remaining, ",") != strings.Join(tt.wantRemaining, ",") {
+				t.Errorf("journal holds %v, want %v", remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestEvaluationOrchestrator_RevertJournal_Empty(t *testing.T) {
+	v := journalOrchestrator(t, nil)
+	reverted, err := v.RevertJournal()
+	if err != nil || len(reverted) != 0 {
+		t.Errorf("expected nothing to revert, got %v, %v", reverted, err)
+	}
+	if _, err := os.Stat(v.changeJournalPath()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no journal file to be written, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package pluginkit
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// RevertPolicy retries a revert function that fails, and bounds how long each
+// attempt may take, so a change to a flaky or slow API is still undone.
+type RevertPolicy struct {
+	// Attempts is the most times a revert function runs, including the
+	// first. Values below 2 disable retries.
+	Attempts int
+	// Timeout bounds a single attempt; zero waits for it to return. An
+	// attempt that times out is abandoned rather than stopped, so a revert
+	// function retried after a timeout must be safe to run again.
+	Timeout time.Duration
+	// Backoff is the wait before the first retry. It doubles before each
+	// further retry.
+	Backoff time.Duration
+}
+
+// ChangeRevert reports what became of one change when the change manager
+// reverted its changes at the end of an evaluation.
+type ChangeRevert struct {
+	Change     string `json:"change" yaml:"change"`
+	TargetName string `json:"target-name" yaml:"target-name"`
+	Applied    bool   `json:"applied" yaml:"applied"`
+	Reverted   bool   `json:"reverted" yaml:"reverted"`
+	Attempts   int    `json:"attempts,omitempty" yaml:"attempts,omitempty"` // Attempts the last revert took; zero when it was never tried
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// SetRevertPolicy sets the revert policy for every change of the manager.
+func (cm *ChangeManager) SetRevertPolicy(policy RevertPolicy) {
+	cm.revertPolicy = policy
+}
+
+// SetRevertPolicy overrides the change manager's revert policy for this
+// change, e.g. to give a slow API a longer timeout. Call it before AddChange.
+func (c *Change) SetRevertPolicy(policy RevertPolicy) {
+	c.revertPolicy = &policy
+}
+
+// revertPolicyFor returns the policy applying to change.
+func (cm *ChangeManager) revertPolicyFor(change *Change) RevertPolicy {
+	if change.revertPolicy != nil {
+		return *change.revertPolicy
+	}
+	return cm.revertPolicy
+}
+
+// run calls revert under the policy, returning how many attempts it took and
+// the error of the last one.
+func (p RevertPolicy) run(revert RevertFunc, data any) (attempts int, err error) {
+	wait := p.Backoff
+	for attempts = 1; ; attempts++ {
+		err = p.attempt(revert, data)
+		if err == nil || attempts >= p.Attempts {
+			return attempts, err
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+func (p RevertPolicy) attempt(revert RevertFunc, data any) error {
+	if p.Timeout <= 0 {
+		return revert(data)
+	}
+	done := make(chan error, 1)
+	go func() { done <- revert(data) }()
+	timer := time.NewTimer(p.Timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("revert timed out after %s", p.Timeout)
+	}
+}
+
+// revertOrder lists the changes RevertAll reverts: those applied through
+// Apply and not yet reverted, newest first, then any change marked applied
+// some other way, by name.
+func (cm *ChangeManager) revertOrder() []string {
+	order := slices.Clone(cm.applied)
+	slices.Reverse(order)
+	var others []string
+	for name, change := range cm.Changes {
+		if change.Applied && !change.Reverted && !slices.Contains(order, name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	return append(order, others...)
+}
+
+// revertReport reports on every change: those named in reverted in that
+// order, then the rest by name.
+func (cm *ChangeManager) revertReport(reverted []string) []ChangeRevert {
+	var rest []string
+	for name := range cm.Changes {
+		if !slices.Contains(reverted, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	var report []ChangeRevert
+	for _, name := range append(slices.Clone(reverted), rest...) {
+		change := cm.Changes[name]
+		entry := ChangeRevert{
+			Change:     name,
+			TargetName: change.TargetName,
+			Applied:    change.Applied,
+			Reverted:   change.Reverted,
+			Attempts:   change.revertAttempts,
+		}
+		if change.Error != nil {
+			entry.Error = change.Error.Error()
+		}
+		report = append(report, entry)
+	}
+	return report
+}
@@ -0,0 +1,185 @@
+package pluginkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// orderedChanges returns a change manager holding the named changes, each
+// recording its name in reverted when its revert function runs.
+func orderedChanges(reverted *[]string, names ...string) *ChangeManager {
+	cm := &ChangeManager{}
+	for _, name := range names {
+		change := pendingChange()
+		change.AddFunctions(goodApplyFunc, func(any) error {
+			*reverted = append(*reverted, name)
+			return nil
+		})
+		cm.AddChange(name, change)
+	}
+	cm.Allow()
+	return cm
+}
+
+func TestChangeManager_RevertAll_Order(t *testing.T) {
+	var reverted []string
+	cm := orderedChanges(&reverted, "network", "bucket", "object", "unused")
+	for _, name := range []string{"network", "bucket", "object"} {
+		if success, _ := cm.Apply(name, name+"-target", nil); !success {
+			t.Fatalf("Apply(%s) failed: %v", name, cm.Changes[name].Error)
+		}
+	}
+	cm.Apply("bucket", "bucket-target", nil) // already applied: its place in the order is kept
+	cm.Changes["legacy"] = &Change{TargetName: "legacy", Description: "marked applied by hand", Applied: true,
+		applyFunc: goodApplyFunc, revertFunc: func(any) error { reverted = append(reverted, "legacy"); return nil }}
+
+	report := cm.RevertAll()
+
+	if want := "object,bucket,network,legacy"; strings.Join(reverted, ",") != want {
+		t.Errorf("reverted in order %v, want %s", reverted, want)
+	}
+	var reported []string
+	for _, entry := range report {
+		reported = append(reported, entry.Change)
+	}
+	if want := "object,bucket,network,legacy,unused"; strings.Join(reported, ",") != want {
+		t.Errorf("report lists %v, want %s", reported, want)
+	}
+	if report[0] != (ChangeRevert{Change: "object", TargetName: "object-target", Applied: true, Reverted: true, Attempts: 1}) {
+		t.Errorf("unexpected report entry %+v", report[0])
+	}
+	if report[4] != (ChangeRevert{Change: "unused", TargetName: "pendingChange"}) {
+		t.Errorf("expected the unapplied change reported as such, got %+v", report[4])
+	}
+
+	reverted = nil
+	cm.RevertAll()
+	if len(reverted) != 0 {
+		t.Errorf("expected reverted changes not to be reverted again, got %v", reverted)
+	}
+}
+
+func TestChangeManager_RevertAll_Policy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        RevertPolicy
+		changePolicy  *RevertPolicy
+		failures      int           // attempts that fail before the revert succeeds
+		delay         time.Duration // how long each attempt takes
+		wantReverted  bool
+		wantAttempts  int
+		wantErr       string
+		wantCorrupted bool
+	}{
+		{
+			name:         "no policy",
+			wantReverted: true,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retried until it succeeds",
+			policy:       RevertPolicy{Attempts: 3, Backoff: time.Millisecond},
+			failures:     2,
+			wantReverted: true,
+			wantAttempts: 3,
+		},
+		{
+			name:          "out of attempts",
+			policy:        RevertPolicy{Attempts: 2},
+			failures:      5,
+			wantAttempts:  2,
+			wantErr:       "revert error",
+			wantCorrupted: true,
+		},
+		{
+			name:          "attempt times out",
+			policy:        RevertPolicy{Timeout: 10 * time.Millisecond},
+			delay:         time.Second,
+			wantAttempts:  1,
+			wantErr:       "revert timed out after 10ms",
+			wantCorrupted: true,
+		},
+		{
+			name:         "change overrides the manager",
+			policy:       RevertPolicy{Timeout: 10 * time.Millisecond},
+			changePolicy: &RevertPolicy{Attempts: 2, Timeout: time.Second},
+			failures:     1,
+			delay:        20 * time.Millisecond,
+			wantReverted: true,
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			change := pendingChange()
+			change.AddFunctions(goodApplyFunc, func(any) error {
+				calls++
+				time.Sleep(tt.delay)
+				if calls <= tt.failures {
+					return errors.New("revert error")
+				}
+				return nil
+			})
+			if tt.changePolicy != nil {
+				change.SetRevertPolicy(*tt.changePolicy)
+			}
+			cm := &ChangeManager{}
+			cm.SetRevertPolicy(tt.policy)
+			cm.AddChange("flaky", change)
+			cm.Allow()
+			cm.Apply("flaky", "target", nil)
+
+			report := cm.RevertAll()
+
+			if len(report) != 1 {
+				t.Fatalf("expected one report entry, got %+v", report)
+			}
+			entry := report[0]
+			if entry.Reverted != tt.wantReverted || entry.Attempts != tt.wantAttempts {
+				t.Errorf("reverted = %v after %d attempts, want %v after %d", entry.Reverted, entry.Attempts, tt.wantReverted, tt.wantAttempts)
+			}
+			if entry.Error != tt.wantErr {
+				t.Errorf("error = %q, want %q", entry.Error, tt.wantErr)
+			}
+			if cm.CorruptedState != tt.wantCorrupted {
+				t.Errorf("CorruptedState = %v, want %v", cm.CorruptedState, tt.wantCorrupted)
+			}
+		})
+	}
+}
+
+func TestEvaluationSuite_Reverts(t *testing.T) {
+	var reverted []string
+	cm := orderedChanges(&reverted, "first", "second")
+	suite := &EvaluationSuite{
+		Name:    "Revert Report Test",
+		catalog: getTestCatalogWithRequirements(),
+		steps: map[string][]gemara.AssessmentStep{
+			"CCC.Core.C01.TR01": {func(any) (gemara.Result, string, gemara.ConfidenceLevel) {
+				cm.Apply("first", "bucket", nil)
+				cm.Apply("second", "bucket-policy", nil)
+				return gemara.Passed, "changes applied", gemara.High
+			}},
+		},
+	}
+	suite.config = setBasicConfig()
+	suite.config.Invasive = true
+	suite.AddChangeManager(cm)
+
+	if err := suite.Evaluate("revertReportTest"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if len(suite.Reverts) != 2 || suite.Reverts[0].Change != "second" || suite.Reverts[1].Change != "first" {
+		t.Fatalf("expected the suite to report both reverts, newest first, got %+v", suite.Reverts)
+	}
+	for _, entry := range suite.Reverts {
+		if !entry.Applied || !entry.Reverted {
+			t.Errorf("expected %s applied and reverted, got %+v", entry.Change, entry)
+		}
+	}
+}
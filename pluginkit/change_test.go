@@ -165,7 +165,7 @@ func TestChange_Revert(t *testing.T) {
 				tt.change.Applied = true
 			}
 
-			tt.change.revert(tt.data)
+			tt.change.revert(tt.data, RevertPolicy{})
 
 			if tt.change.Reverted != tt.expectedReverted {
 				t.Errorf("Reverted = %v, expected %v", tt.change.Reverted, tt.expectedReverted)
@@ -0,0 +1,192 @@
+package pluginkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// ContextAssessmentStep is the context-carrying variant of TypedAssessmentStep.
+// The context is cancelled when the step exceeds the configured step-timeout
+// or the run exceeds its run-timeout, so a step making network calls should
+// pass it to them and return promptly once it is done.
+//
+// As with the typed helpers, the registration functions below constrain S to
+// ~ContextAssessmentStep[T] so plugins may keep their own named step type.
+type ContextAssessmentStep[T any] func(context.Context, T) (gemara.Result, string, gemara.ConfidenceLevel)
+
+// contextStep is a ContextAssessmentStep after the SDK has taken over the
+// payload type assertion.
+type contextStep func(context.Context, any) (gemara.Result, string, gemara.ConfidenceLevel)
+
+// adaptContextSteps converts context-carrying steps into the form the suite
+// stores. Alongside the names it returns two parallel maps: the contextStep the
+// suite binds to its run context at evaluation time, and a gemara.AssessmentStep
+// stand-in (run with context.Background) so the step still occupies its slot in
+// the gemara log and serializes like any other step.
+func adaptContextSteps[S ~func(context.Context, T) (gemara.Result, string, gemara.ConfidenceLevel), T any](
+	steps map[string][]S,
+) registeredSteps {
+	registered := registeredSteps{
+		steps:        make(map[string][]gemara.AssessmentStep, len(steps)),
+		names:        make(map[string][]string, len(steps)),
+		contextSteps: make(map[string][]contextStep, len(steps)),
+		payloadType:  reflect.TypeFor[T](),
+	}
+	for id, list := range steps {
+		for _, step := range list {
+			fn := step // capture per iteration, not the loop variable
+			withContext := func(ctx context.Context, payload any) (gemara.Result, string, gemara.ConfidenceLevel) {
+				typed, ok := payload.(T)
+				if !ok {
+					var zero T
+					return gemara.Unknown, fmt.Sprintf("expected %T, got %T", zero, payload), 0
+				}
+				return fn(ctx, typed)
+			}
+			registered.names[id] = append(registered.names[id], FuncName(fn))
+			registered.contextSteps[id] = append(registered.contextSteps[id], withContext)
+			registered.steps[id] = append(registered.steps[id], func(payload any) (gemara.Result, string, gemara.ConfidenceLevel) {
+				return withContext(context.Background(), payload)
+			})
+		}
+	}
+	return registered
+}
+
+// AddEvaluationSuiteContext registers an evaluation suite whose steps take a
+// context.Context and a concrete payload type T. It behaves like
+// AddEvaluationSuiteTyped, and additionally hands each step a context that is
+// cancelled at the step-timeout or run-timeout deadline.
+//
+// Steps registered through any of the helpers are bounded by those deadlines;
+// a step that overruns is recorded as Unknown. Only context-carrying steps can
+// notice the cancellation and stop their own work.
+func AddEvaluationSuiteContext[S ~func(context.Context, T) (gemara.Result, string, gemara.ConfidenceLevel), T any](
+	v *EvaluationOrchestrator, catalogId string, loader DataLoader, steps map[string][]S,
+) error {
+	return v.addEvaluationSuiteNamed(catalogId, loader, adaptContextSteps[S, T](steps))
+}
+
+// AddEvaluationSuiteContextForAllCatalogs is AddEvaluationSuiteContext applied
+// to every reference catalog loaded via AddReferenceCatalogs, mirroring
+// AddEvaluationSuiteForAllCatalogs.
+func AddEvaluationSuiteContextForAllCatalogs[S ~func(context.Context, T) (gemara.Result, string, gemara.ConfidenceLevel), T any](
+	v *EvaluationOrchestrator, loader DataLoader, steps map[string][]S,
+) error {
+	if len(v.referenceCatalogs) == 0 {
+		return BAD_CATALOG(v.PluginName, "no reference catalogs loaded", "aac10")
+	}
+	registered := adaptContextSteps[S, T](steps)
+	for catalogId := range v.referenceCatalogs {
+		if err := v.addEvaluationSuiteNamed(catalogId, loader, registered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runContext returns the suite's run context, which is only set while it evaluates.
+func (e *EvaluationSuite) runContext() context.Context {
+	if e.runCtx == nil {
+		return context.Background()
+	}
+	return e.runCtx
+}
+
+// deadlineSteps binds each step of requirementId to the run context and the
+// configured step timeout, adding any evidence a context step attaches to
+// evidence. Steps are returned unchanged when neither deadline applies and
+// none of them take a context.
+func (e *EvaluationSuite) deadlineSteps(requirementId string, steps []gemara.AssessmentStep, evidence *[]gemara.Evidence) []gemara.AssessmentStep {
+	withContext := e.contextSteps[requirementId]
+	if len(steps) == 0 || (len(withContext) == 0 && e.stepTimeout() == 0 && e.runContext().Done() == nil) {
+		return steps
+	}
+	bound := make([]gemara.AssessmentStep, len(steps))
+	for i, step := range steps {
+		var ctxStep contextStep
+		if i < len(withContext) {
+			ctxStep = withContext[i]
+		}
+		name := e.stepName(requirementId, i, step)
+		bound[i] = func(payload any) (gemara.Result, string, gemara.ConfidenceLevel) {
+			result, message, confidence, attached := e.runStep(name, step, ctxStep, payload)
+			appendEvidence(requirementId, evidence, attached)
+			return result, message, confidence
+		}
+	}
+	return bound
+}
+
+func (e *EvaluationSuite) stepTimeout() (timeout time.Duration) {
+	if e.config != nil {
+		timeout = e.config.StepTimeout
+	}
+	return timeout
+}
+
+// runStep runs a single step under the run context and step timeout. A step
+// still running at the deadline is recorded as Unknown and abandoned: its
+// goroutine keeps running until it returns, so steps that ignore the context
+// should not mutate the payload. The evidence the step attached through its
+// context is returned alongside its outcome, and dropped if it was abandoned.
+func (e *EvaluationSuite) runStep(name string, step gemara.AssessmentStep, ctxStep contextStep, payload any) (gemara.Result, string, gemara.ConfidenceLevel, []gemara.Evidence) {
+	ctx := e.runContext()
+	if ctx.Err() != nil {
+		return gemara.Unknown, fmt.Sprintf("step %s was not started: %s", name, e.deadlineReason(ctx)), gemara.Undetermined, nil
+	}
+	if timeout := e.stepTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ctx, attached := withStepEvidence(ctx)
+
+	type outcome struct {
+		result     gemara.Result
+		message    string
+		confidence gemara.ConfidenceLevel
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		var o outcome
+		if ctxStep != nil {
+			o.result, o.message, o.confidence = ctxStep(ctx, payload)
+		} else {
+			o.result, o.message, o.confidence = step(payload)
+		}
+		done <- o
+	}()
+
+	select {
+	case o := <-done:
+		// A context step that returns because its context ended reports
+		// whatever its cancelled call produced; record the deadline instead.
+		if ctx.Err() == nil {
+			return o.result, o.message, o.confidence, attached.close()
+		}
+	case <-ctx.Done():
+	}
+	attached.close()
+	return gemara.Unknown, fmt.Sprintf("step %s did not complete: %s", name, e.deadlineReason(ctx)), gemara.Undetermined, nil
+}
+
+// deadlineReason explains why ctx ended, distinguishing the run deadline from
+// the per-step one so the message points at the right config key.
+func (e *EvaluationSuite) deadlineReason(ctx context.Context) string {
+	if runErr := e.runContext().Err(); runErr != nil {
+		if errors.Is(runErr, context.DeadlineExceeded) {
+			return "run-timeout exceeded"
+		}
+		return "run was cancelled"
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Sprintf("step-timeout of %s exceeded", e.stepTimeout())
+	}
+	return "step was cancelled"
+}
@@ -0,0 +1,184 @@
+package pluginkit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+func contextStep_Pass(_ context.Context, _ testPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+	return gemara.Passed, "context step ok", gemara.High
+}
+
+// contextStep_WaitForCancel blocks until the SDK cancels its context, as a
+// well-behaved network-bound step would.
+func contextStep_WaitForCancel(ctx context.Context, _ testPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+	<-ctx.Done()
+	return gemara.Failed, "should have been superseded by the timeout", gemara.High
+}
+
+func step_Hang(_ interface{}) (gemara.Result, string, gemara.ConfidenceLevel) {
+	time.Sleep(time.Second)
+	return gemara.Passed, "finished too late", gemara.High
+}
+
+func TestAdaptContextSteps_CapturesNamesAndContext(t *testing.T) {
+	registered := adaptContextSteps[ContextAssessmentStep[testPayload], testPayload](map[string][]ContextAssessmentStep[testPayload]{
+		"CCC.Core.C01.TR01": {contextStep_Pass},
+	})
+
+	names := registered.names["CCC.Core.C01.TR01"]
+	if len(names) != 1 || !strings.HasSuffix(names[0], "contextStep_Pass") {
+		t.Errorf("expected the real step name, got %v", names)
+	}
+	if len(registered.steps["CCC.Core.C01.TR01"]) != 1 || len(registered.contextSteps["CCC.Core.C01.TR01"]) != 1 {
+		t.Fatalf("expected one step and one context step, got %d and %d",
+			len(registered.steps["CCC.Core.C01.TR01"]), len(registered.contextSteps["CCC.Core.C01.TR01"]))
+	}
+
+	result, message, _ := registered.steps["CCC.Core.C01.TR01"][0]("wrong payload")
+	if result != gemara.Unknown || !strings.Contains(message, "expected") {
+		t.Errorf("expected a payload type mismatch to be Unknown, got %v %q", result, message)
+	}
+}
+
+func TestStepTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(*EvaluationOrchestrator) error
+		wantStep string // the step the written log should name, when it is not an adapter
+	}{
+		{
+			name:     "plain step is abandoned",
+			wantStep: "step_Hang",
+			register: func(v *EvaluationOrchestrator) error {
+				return v.AddEvaluationSuite("CCC.ObjStor", nil, map[string][]gemara.AssessmentStep{"CCC.Core.C01.TR01": {step_Hang}})
+			},
+		},
+		{
+			name: "context step is cancelled",
+			register: func(v *EvaluationOrchestrator) error {
+				return AddEvaluationSuiteContext(v, "CCC.ObjStor", nil, map[string][]ContextAssessmentStep[testPayload]{
+					"CCC.Core.C01.TR01": {contextStep_WaitForCancel},
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setBasicConfig()
+			cfg.StepTimeout = 20 * time.Millisecond
+			catalog := getTestCatalogWithRequirements()
+			v := &EvaluationOrchestrator{
+				config:            cfg,
+				referenceCatalogs: map[string]*gemara.ControlCatalog{catalog.Metadata.Id: catalog},
+			}
+			if err := tt.register(v); err != nil {
+				t.Fatalf("registering suite: %v", err)
+			}
+			suite := v.possibleSuites[0]
+			suite.payload = testPayload{}
+
+			if err := suite.Evaluate("timeout"); err != nil {
+				t.Fatalf("Evaluate failed: %v", err)
+			}
+			assessment := suite.EvaluationLog.Evaluations[0].AssessmentLogs[0]
+			if assessment.Result != gemara.Unknown {
+				t.Errorf("expected Unknown, got %v", assessment.Result)
+			}
+			if !strings.Contains(assessment.Message, "step-timeout of 20ms exceeded") {
+				t.Errorf("expected a step-timeout message, got %q", assessment.Message)
+			}
+			if tt.wantStep != "" && !strings.HasSuffix(assessment.Steps[0].String(), tt.wantStep) {
+				t.Errorf("expected the deadline wrapper to be restored to %s, got %s", tt.wantStep, assessment.Steps[0].String())
+			}
+		})
+	}
+}
+
+func TestRunTimeout_WritesPartialResults(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := setBasicConfig()
+	cfg.Policy.ControlCatalogs = []string{"CCC.ObjStor"}
+	cfg.Write = true
+	cfg.WriteDirectory = tmpDir
+	cfg.Output = []string{"yaml"}
+	cfg.RunTimeout = 20 * time.Millisecond
+
+	catalog := getTestCatalogWithRequirements()
+	v := &EvaluationOrchestrator{
+		PluginName:        "test-plugin",
+		config:            cfg,
+		referenceCatalogs: map[string]*gemara.ControlCatalog{catalog.Metadata.Id: catalog},
+	}
+	err := AddEvaluationSuiteContext(v, "CCC.ObjStor", nil, map[string][]ContextAssessmentStep[testPayload]{
+		"CCC.Core.C01.TR01": {contextStep_WaitForCancel, contextStep_Pass},
+	})
+	if err != nil {
+		t.Fatalf("registering suite: %v", err)
+	}
+	v.AddLoader(func(_ *config.Config) (any, error) { return testPayload{}, nil })
+
+	if err := v.Mobilize(); err != nil {
+		t.Fatalf("Mobilize failed: %v", err)
+	}
+
+	assessment := v.Evaluation_Suites[0].EvaluationLog.Evaluations[0].AssessmentLogs[0]
+	if assessment.Result != gemara.Unknown {
+		t.Errorf("expected Unknown, got %v", assessment.Result)
+	}
+	if !strings.Contains(assessment.Message, "run-timeout exceeded") {
+		t.Errorf("expected a run-timeout message, got %q", assessment.Message)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "test-service", "test-service.yaml")); err != nil {
+		t.Errorf("expected partial results to be written: %v", err)
+	}
+}
+
+func TestRunTimeout_AbandonsLoader(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := setBasicConfig()
+	cfg.Policy.ControlCatalogs = []string{"CCC.ObjStor"}
+	cfg.Write = true
+	cfg.WriteDirectory = tmpDir
+	cfg.Output = []string{"yaml"}
+	cfg.RunTimeout = 20 * time.Millisecond
+
+	catalog := getTestCatalogWithRequirements()
+	v := &EvaluationOrchestrator{
+		PluginName:        "test-plugin",
+		config:            cfg,
+		referenceCatalogs: map[string]*gemara.ControlCatalog{catalog.Metadata.Id: catalog},
+	}
+	err := AddEvaluationSuiteContext(v, "CCC.ObjStor", nil, map[string][]ContextAssessmentStep[testPayload]{
+		"CCC.Core.C01.TR01": {contextStep_Pass},
+	})
+	if err != nil {
+		t.Fatalf("registering suite: %v", err)
+	}
+	v.AddLoader(func(_ *config.Config) (any, error) {
+		time.Sleep(time.Second)
+		return testPayload{}, nil
+	})
+
+	if err := v.Mobilize(); err != nil {
+		t.Fatalf("expected the loader to be abandoned at the run deadline without failing the run, got %v", err)
+	}
+
+	for _, evaluation := range v.Evaluation_Suites[0].EvaluationLog.Evaluations {
+		for _, assessment := range evaluation.AssessmentLogs {
+			if assessment.Result != gemara.Unknown || !strings.Contains(assessment.Message, "run-timeout exceeded") {
+				t.Errorf("expected %s recorded as Unknown with the run timeout, got %v: %q", assessment.Requirement.EntryId, assessment.Result, assessment.Message)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "test-service", "test-service.yaml")); err != nil {
+		t.Errorf("expected results to be written: %v", err)
+	}
+}
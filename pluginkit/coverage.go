@@ -0,0 +1,115 @@
+package pluginkit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// CoverageCommand is the plugin subcommand that reports step coverage.
+// command.NewPluginCommands wires it onto every plugin, and `pvtr publish`
+// execs it with --json when a coverage gate is requested.
+const CoverageCommand = "coverage"
+
+// CoverageReport is how much of each evaluated catalog has steps registered.
+type CoverageReport struct {
+	Catalogs []CatalogCoverage `json:"catalogs"`
+}
+
+// CatalogCoverage is the step coverage of one evaluation suite. A requirement
+// without steps is recorded as Unknown whenever it runs; an orphaned step id
+// names no requirement in the catalog and so never runs at all.
+type CatalogCoverage struct {
+	CatalogId    string   `json:"catalog-id"`
+	Requirements int      `json:"requirements"`
+	Covered      []string `json:"covered"`
+	Uncovered    []string `json:"uncovered"`
+	Orphaned     []string `json:"orphaned"`
+	// Percent is the share of requirements with at least one step.
+	Percent float64 `json:"percent"`
+}
+
+// Coverage reports, for every registered evaluation suite, which catalog
+// requirements have steps registered, which do not, and which registered step
+// ids match no requirement. Like PublishManifest it needs no config.
+func (v *EvaluationOrchestrator) Coverage() CoverageReport {
+	report := CoverageReport{Catalogs: []CatalogCoverage{}}
+	for _, suite := range v.possibleSuites {
+		coverage := CatalogCoverage{
+			CatalogId: suite.CatalogId,
+			Covered:   []string{},
+			Uncovered: []string{},
+			Orphaned:  []string{},
+		}
+		defined := make(map[string]bool)
+		for _, control := range suite.catalog.Controls {
+			for _, requirement := range control.AssessmentRequirements {
+				if defined[requirement.Id] {
+					continue
+				}
+				defined[requirement.Id] = true
+				if len(suite.steps[requirement.Id]) > 0 {
+					coverage.Covered = append(coverage.Covered, requirement.Id)
+				} else {
+					coverage.Uncovered = append(coverage.Uncovered, requirement.Id)
+				}
+			}
+		}
+		for id := range suite.steps {
+			if !defined[id] {
+				coverage.Orphaned = append(coverage.Orphaned, id)
+			}
+		}
+		sort.Strings(coverage.Orphaned)
+		coverage.Requirements = len(defined)
+		if coverage.Requirements > 0 {
+			coverage.Percent = 100 * float64(len(coverage.Covered)) / float64(coverage.Requirements)
+		}
+		report.Catalogs = append(report.Catalogs, coverage)
+	}
+	sort.Slice(report.Catalogs, func(i, j int) bool { return report.Catalogs[i].CatalogId < report.Catalogs[j].CatalogId })
+	return report
+}
+
+// Check fails when a catalog's coverage is below minPercent, or when
+// rejectOrphaned is set and any step id matches no requirement. A minPercent
+// of zero disables the threshold.
+func (r CoverageReport) Check(minPercent float64, rejectOrphaned bool) error {
+	var problems []string
+	for _, catalog := range r.Catalogs {
+		if catalog.Percent < minPercent {
+			problems = append(problems, fmt.Sprintf("%s covers %.1f%% of requirements, below the %.1f%% minimum (%d uncovered)",
+				catalog.CatalogId, catalog.Percent, minPercent, len(catalog.Uncovered)))
+		}
+		if rejectOrphaned && len(catalog.Orphaned) > 0 {
+			problems = append(problems, fmt.Sprintf("%s has steps for ids not in the catalog: %s",
+				catalog.CatalogId, strings.Join(catalog.Orphaned, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("step coverage check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Render writes the coverage report as a human-readable table, followed by
+// the uncovered and orphaned ids of each catalog.
+func (r CoverageReport) Render(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "CATALOG\tCOVERED\tUNCOVERED\tORPHANED\tCOVERAGE")
+	for _, catalog := range r.Catalogs {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.1f%%\n", catalog.CatalogId,
+			len(catalog.Covered), len(catalog.Uncovered), len(catalog.Orphaned), catalog.Percent)
+	}
+	_ = tw.Flush()
+	for _, catalog := range r.Catalogs {
+		if len(catalog.Uncovered) > 0 {
+			_, _ = fmt.Fprintf(w, "%s uncovered: %s\n", catalog.CatalogId, strings.Join(catalog.Uncovered, ", "))
+		}
+		if len(catalog.Orphaned) > 0 {
+			_, _ = fmt.Fprintf(w, "%s orphaned step ids: %s\n", catalog.CatalogId, strings.Join(catalog.Orphaned, ", "))
+		}
+	}
+}
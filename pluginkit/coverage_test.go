@@ -0,0 +1,63 @@
+package pluginkit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+func TestEvaluationOrchestrator_Coverage(t *testing.T) {
+	orchestrator := &EvaluationOrchestrator{}
+	orchestrator.addEvaluationSuite(getTestCatalogWithControls(4), nil, registeredSteps{steps: map[string][]gemara.AssessmentStep{
+		"CCC.Core.C00.TR01": {step_Pass},
+		"CCC.Core.C02.TR01": {step_Pass, step_Fail},
+		"CCC.Core.C09.TR01": {step_Pass}, // not in the catalog
+		"CCC.Core.C03.TR01": {},          // registered without steps
+	}})
+	orchestrator.addEvaluationSuite(getTestCatalogWithID("CCC.ObjStor"), nil, registeredSteps{steps: createPassingStepsMap()})
+
+	report := orchestrator.Coverage()
+	if len(report.Catalogs) != 2 || report.Catalogs[0].CatalogId != "CCC.ObjStor" {
+		t.Fatalf("expected catalogs sorted by id, got %+v", report.Catalogs)
+	}
+	parallel := report.Catalogs[1]
+	if strings.Join(parallel.Covered, ",") != "CCC.Core.C00.TR01,CCC.Core.C02.TR01" ||
+		strings.Join(parallel.Uncovered, ",") != "CCC.Core.C01.TR01,CCC.Core.C03.TR01" ||
+		strings.Join(parallel.Orphaned, ",") != "CCC.Core.C09.TR01" ||
+		parallel.Requirements != 4 || parallel.Percent != 50 {
+		t.Errorf("unexpected coverage: %+v", parallel)
+	}
+
+	tests := []struct {
+		name           string
+		minPercent     float64
+		rejectOrphaned bool
+		wantErr        string
+	}{
+		{name: "no gate"},
+		{name: "threshold met", minPercent: 50},
+		{name: "below threshold", minPercent: 75, wantErr: "CCC.Parallel covers 50.0% of requirements, below the 75.0% minimum (2 uncovered)"},
+		{name: "orphaned steps", rejectOrphaned: true, wantErr: "CCC.Parallel has steps for ids not in the catalog: CCC.Core.C09.TR01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := report.Check(tt.minPercent, tt.rejectOrphaned)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	report.Render(&out)
+	for _, s := range []string{"CCC.ObjStor", "100.0%", "CCC.Parallel uncovered: CCC.Core.C01.TR01, CCC.Core.C03.TR01", "orphaned step ids: CCC.Core.C09.TR01"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("rendered report is missing %q:\n%s", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package pluginkit
+
+import (
+	"runtime/debug"
+	"slices"
+	"sort"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+// DescribeCommand is the plugin subcommand that emits the plugin's capability
+// metadata as JSON, so harnesses and docs tooling can introspect a binary
+// without running it.
+const DescribeCommand = "describe"
+
+// DescriptionSchema identifies the describe output format for machine consumers.
+const DescriptionSchema = "privateer-plugin-description/v1"
+
+// sdkModulePath is the module whose version Describe reports.
+const sdkModulePath = "github.com/privateerproj/privateer-sdk"
+
+// Description is everything a plugin binary can do, read from what the plugin
+// author registered on the orchestrator.
+type Description struct {
+	Schema        string   `json:"schema"`
+	SDKVersion    string   `json:"sdk-version"`
+	PluginName    string   `json:"plugin-name"`
+	PluginVersion string   `json:"plugin-version,omitempty"`
+	PluginUri     string   `json:"plugin-uri,omitempty"`
+	Publisher     string   `json:"publisher,omitempty"`
+	RequiredVars  []string `json:"required-vars"`
+	OutputFormats []string `json:"output-formats"`
+	// TestSuites are the named requirement sets declared with AddTestSuite.
+	TestSuites map[string][]string  `json:"test-suites,omitempty"`
+	Catalogs   []CatalogDescription `json:"catalogs"`
+}
+
+// CatalogDescription is one reference catalog and what the plugin evaluates of it.
+type CatalogDescription struct {
+	Id      string `json:"id"`
+	Title   string `json:"title"`
+	Version string `json:"version,omitempty"`
+	// Evaluated is true when an evaluation suite is registered for the catalog.
+	Evaluated               bool                     `json:"evaluated"`
+	ApplicabilityCategories []gemara.Group           `json:"applicability-categories,omitempty"`
+	Requirements            []RequirementDescription `json:"requirements"`
+	// Changes are the invasive changes registered with AddChangeManager.
+	Changes []ChangeDescription `json:"changes,omitempty"`
+}
+
+// RequirementDescription is one assessment requirement and the steps bound to it.
+type RequirementDescription struct {
+	Control       string   `json:"control"`
+	Requirement   string   `json:"requirement"`
+	Text          string   `json:"text,omitempty"`
+	Applicability []string `json:"applicability"`
+	Steps         []string `json:"steps"`
+}
+
+// ChangeDescription is an invasive change a suite's steps may apply.
+type ChangeDescription struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Describe assembles the plugin's capability metadata. Like PublishManifest it
+// needs no config: it reads only what the plugin registered at construction.
+func (v *EvaluationOrchestrator) Describe() Description {
+	description := Description{
+		Schema:        DescriptionSchema,
+		SDKVersion:    sdkVersion(),
+		PluginName:    v.PluginName,
+		PluginVersion: v.PluginVersion,
+		PluginUri:     v.PluginUri,
+		Publisher:     v.Publisher,
+		RequiredVars:  append([]string{}, v.requiredVars...),
+		OutputFormats: config.OutputTypes(),
+		TestSuites:    v.testSuites,
+		Catalogs:      []CatalogDescription{},
+	}
+
+	catalogIds := make([]string, 0, len(v.referenceCatalogs))
+	for id := range v.referenceCatalogs {
+		catalogIds = append(catalogIds, id)
+	}
+	sort.Strings(catalogIds)
+	for _, id := range catalogIds {
+		catalog := v.referenceCatalogs[id]
+		catalogDescription := CatalogDescription{
+			Id:                      id,
+			Title:                   catalog.Title,
+			Version:                 catalog.Metadata.Version,
+			ApplicabilityCategories: catalog.Metadata.ApplicabilityGroups,
+		}
+		var suite *EvaluationSuite
+		if index := slices.IndexFunc(v.possibleSuites, func(s *EvaluationSuite) bool { return s.CatalogId == id }); index >= 0 {
+			suite = v.possibleSuites[index]
+			catalog = suite.catalog // includes imported controls
+			catalogDescription.Evaluated = true
+		}
+		for _, control := range catalog.Controls {
+			for _, requirement := range control.AssessmentRequirements {
+				requirementDescription := RequirementDescription{
+					Control:       control.Id,
+					Requirement:   requirement.Id,
+					Text:          requirement.Text,
+					Applicability: requirement.Applicability,
+					Steps:         []string{},
+				}
+				if suite != nil {
+					for i, step := range suite.steps[requirement.Id] {
+						requirementDescription.Steps = append(requirementDescription.Steps, suite.stepName(requirement.Id, i, step))
+					}
+				}
+				catalogDescription.Requirements = append(catalogDescription.Requirements, requirementDescription)
+			}
+		}
+		for _, change := range planChanges(v.changeManagers[id], false) {
+			catalogDescription.Changes = append(catalogDescription.Changes, ChangeDescription{Name: change.Name, Description: change.Description})
+		}
+		description.Catalogs = append(description.Catalogs, catalogDescription)
+	}
+	return description
+}
+
+// sdkVersion reports the privateer-sdk module version the binary was built
+// with, or "(devel)" when that cannot be read from the build info.
+func sdkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == sdkModulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == sdkModulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
@@ -0,0 +1,70 @@
+package pluginkit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+func TestEvaluationOrchestrator_Describe(t *testing.T) {
+	described := getTestCatalogWithID("CCC.ObjStor")
+	described.Title = "Object Storage"
+	described.Metadata.Version = "v2025.01"
+	described.Metadata.ApplicabilityGroups = []gemara.Group{{Id: "tlp-green", Title: "TLP Green"}}
+	unevaluated := getTestCatalogWithID("CCC.KeyMgmt")
+
+	orchestrator := &EvaluationOrchestrator{
+		PluginName:        "test-plugin",
+		PluginVersion:     "1.2.3",
+		referenceCatalogs: map[string]*gemara.ControlCatalog{"CCC.ObjStor": described, "CCC.KeyMgmt": unevaluated},
+	}
+	orchestrator.AddRequiredVars([]string{"token"})
+	orchestrator.addEvaluationSuite(described, nil, registeredSteps{
+		steps: map[string][]gemara.AssessmentStep{"CCC.Core.C01.TR01": {step_Pass, step_Fail}},
+		names: map[string][]string{"CCC.Core.C01.TR01": {"checks.TLS", "checks.Cipher"}},
+	})
+	if err := orchestrator.AddTestSuite("quick", []string{"CCC.Core.C01.TR01"}); err != nil {
+		t.Fatal(err)
+	}
+	cm := &ChangeManager{}
+	cm.AddChange("disable-tls", Change{Description: "Turn TLS off to prove the check notices"})
+	orchestrator.AddChangeManager("CCC.ObjStor", cm)
+
+	description := orchestrator.Describe()
+	if description.Schema != DescriptionSchema || description.SDKVersion == "" || description.PluginVersion != "1.2.3" {
+		t.Errorf("unexpected header: %+v", description)
+	}
+	if strings.Join(description.RequiredVars, ",") != "token" || !strings.Contains(strings.Join(description.OutputFormats, ","), "oscal") {
+		t.Errorf("unexpected vars/formats: %v %v", description.RequiredVars, description.OutputFormats)
+	}
+	if len(description.Catalogs) != 2 || description.Catalogs[0].Id != "CCC.KeyMgmt" {
+		t.Fatalf("expected catalogs sorted by id, got %+v", description.Catalogs)
+	}
+	if keyMgmt := description.Catalogs[0]; keyMgmt.Evaluated || len(keyMgmt.Requirements[0].Steps) != 0 {
+		t.Errorf("a catalog without a suite has no steps: %+v", keyMgmt)
+	}
+
+	objStor := description.Catalogs[1]
+	if !objStor.Evaluated || objStor.Version != "v2025.01" || objStor.ApplicabilityCategories[0].Id != "tlp-green" {
+		t.Errorf("unexpected catalog: %+v", objStor)
+	}
+	requirement := objStor.Requirements[0]
+	if requirement.Requirement != "CCC.Core.C01.TR01" || strings.Join(requirement.Steps, ",") != "checks.TLS,checks.Cipher" {
+		t.Errorf("unexpected requirement: %+v", requirement)
+	}
+	if len(objStor.Changes) != 1 || objStor.Changes[0].Name != "disable-tls" {
+		t.Errorf("unexpected changes: %+v", objStor.Changes)
+	}
+
+	data, err := json.Marshal(description)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"sdk-version"`, `"test-suites":{"quick":["CCC.Core.C01.TR01"]}`, `"required-vars":["token"]`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("JSON is missing %s: %s", key, data)
+		}
+	}
+}
@@ -68,9 +68,24 @@ var (
 	NO_MATCHING_CATALOGS = func(requested []string, available []string, mod string) error {
 		return wrap(ErrDevBug, fmt.Sprintf("no requested catalogs matched available suites. requested=%v available=%v", requested, available), mod)
 	}
+	BAD_TEST_SUITE = func(pluginName string, errMsg string, mod string) error {
+		return wrap(ErrDevBug, fmt.Sprintf("malformed test suite for %s: %s", pluginName, errMsg), mod)
+	}
+	UNKNOWN_TEST_SUITE = func(requested string, available []string, mod string) error {
+		return wrap(ErrDevBug, fmt.Sprintf("requested test suite is not declared by the plugin. requested=%s available=%v", requested, available), mod)
+	}
+	BAD_PREREQUISITES = func(pluginName string, errMsg string, mod string) error {
+		return wrap(ErrDevBug, fmt.Sprintf("malformed prerequisites for %s: %s", pluginName, errMsg), mod)
+	}
 	BENCHMARK_WRITE_FAILED = func(err error, mod string) error {
 		return wrap(ErrRuntime, fmt.Sprintf("failed to write benchmark report: %s", err), mod)
 	}
+	CHANGE_JOURNAL_FAILED = func(err error, mod string) error {
+		return wrap(ErrRuntime, fmt.Sprintf("failed to use the change journal: %s", err), mod)
+	}
+	REVERT_FAILED = func(errMsg string, mod string) error {
+		return wrap(ErrRuntime, fmt.Sprintf("failed to revert journaled changes: %s", errMsg), mod)
+	}
 )
 
 // wrap chains the category sentinel via %w so errors.Is works, while keeping
@@ -1,17 +1,19 @@
 package pluginkit
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/gemaraproj/go-gemara"
-	"github.com/gemaraproj/go-gemara/gemaraconv"
 	"github.com/goccy/go-yaml"
 	"github.com/privateerproj/privateer-sdk/config"
 	"github.com/privateerproj/privateer-sdk/utils"
@@ -41,18 +43,26 @@ type EvaluationOrchestrator struct {
 	// namespace that owns it, for plugins that evaluate catalogs published by
 	// someone else (e.g. a community plugin evaluating ossf/osps-baseline).
 	// Catalogs not listed here are assumed to live under Publisher.
-	CatalogNamespaces map[string]string  `json:"catalog-namespaces,omitempty" yaml:"catalog-namespaces,omitempty"`
+	CatalogNamespaces map[string]string `json:"catalog-namespaces,omitempty" yaml:"catalog-namespaces,omitempty"`
+	// TestSuite is the named set of requirements the run executed; see AddTestSuite.
+	TestSuite         string             `json:"test-suite,omitempty" yaml:"test-suite,omitempty"`
 	Payload           any                `json:"payload,omitempty" yaml:"payload,omitempty"`
 	Evaluation_Suites []*EvaluationSuite `json:"evaluation-suites" yaml:"evaluation-suites"` // EvaluationSuite is a map of evaluations to their catalog names
 
-	possibleSuites    []*EvaluationSuite
-	possibleControls  map[string][]*gemara.Control
-	referenceCatalogs map[string]*gemara.ControlCatalog
-	requiredVars      []string
-	config            *config.Config
-	loader            DataLoader
-	targetBuilder     TargetBuilder
-	benchmark         *BenchmarkReport
+	possibleSuites     []*EvaluationSuite
+	possibleControls   map[string][]*gemara.Control
+	referenceCatalogs  map[string]*gemara.ControlCatalog
+	requiredVars       []string
+	testSuites         map[string][]string // requirement ids by test suite name
+	retryPolicy        RetryPolicy
+	requirementRetries map[string]RetryPolicy
+	changeManagers     map[string]*ChangeManager // by catalog id
+	config             *config.Config
+	loader             DataLoader
+	payloadType        reflect.Type // what a replayed orchestrator payload decodes into
+	targetBuilder      TargetBuilder
+	benchmark          *BenchmarkReport
+	stdout             io.Writer // streamed results; nil means os.Stdout
 }
 
 // DataLoader is a function type for loading plugin data from configuration.
@@ -70,6 +80,15 @@ func (v *EvaluationOrchestrator) AddLoader(loader DataLoader) {
 	v.loader = loader
 }
 
+// SetPayloadType declares the type the orchestrator loader returns, by
+// example: v.SetPayloadType(data.Payload{}). Replay mode decodes the recorded
+// orchestrator payload into it. Suites registered with the typed or context
+// helpers already know their payload type, so this is only needed when every
+// suite sharing the orchestrator payload takes an untyped any.
+func (v *EvaluationOrchestrator) SetPayloadType(prototype any) {
+	v.payloadType = reflect.TypeOf(prototype)
+}
+
 // AddTargetBuilder sets the function that identifies the evaluated resource
 // in each emitted EvaluationLog. Fields left empty by the builder are
 // backfilled from the service name so the log always identifies its target.
@@ -77,6 +96,28 @@ func (v *EvaluationOrchestrator) AddTargetBuilder(builder TargetBuilder) {
 	v.targetBuilder = builder
 }
 
+// AddChangeManager registers the invasive changes the steps of a catalog's
+// suite may apply. The manager is attached to the suite when it runs, and only
+// allowed to apply changes when the service is invasive; registering it up
+// front lets plan mode list the changes without running anything.
+func (v *EvaluationOrchestrator) AddChangeManager(catalogId string, cm *ChangeManager) {
+	if v.changeManagers == nil {
+		v.changeManagers = make(map[string]*ChangeManager)
+	}
+	v.changeManagers[catalogId] = cm
+}
+
+// SetConfig makes the orchestrator use c rather than building its config from
+// viper when it runs, e.g. to run a plugin against an in-memory config in a
+// test. c.Error is checked by Mobilize as usual, but the vars named by
+// AddRequiredVars are only checked when the config is built from viper.
+func (v *EvaluationOrchestrator) SetConfig(c *config.Config) {
+	v.config = c
+	for _, suite := range v.possibleSuites {
+		suite.config = c
+	}
+}
+
 // AddRequiredVars sets the required configuration variables for the orchestrator.
 func (v *EvaluationOrchestrator) AddRequiredVars(vars []string) {
 	v.requiredVars = vars
@@ -95,18 +136,31 @@ func (v *EvaluationOrchestrator) AddReferenceCatalogs(dataDir string, files embe
 		return err
 	}
 	for _, catalog := range catalogs {
-		if catalog.Metadata.Id == "" {
-			return errors.New("catalog id cannot be empty")
-		}
-		if _, exists := v.referenceCatalogs[catalog.Metadata.Id]; exists {
-			return fmt.Errorf("duplicate catalog id found: %s", catalog.Metadata.Id)
+		if err := v.AddReferenceCatalog(catalog); err != nil {
+			return err
 		}
-		v.referenceCatalogs[catalog.Metadata.Id] = catalog
-		v.addPossibleControls(catalog)
 	}
 	return nil
 }
 
+// AddReferenceCatalog adds one catalog that is already in memory, such as a
+// catalog built inline by a test. Suites are registered against it by id, as
+// for the catalogs loaded by AddReferenceCatalogs.
+func (v *EvaluationOrchestrator) AddReferenceCatalog(catalog *gemara.ControlCatalog) error {
+	if v.referenceCatalogs == nil {
+		v.referenceCatalogs = make(map[string]*gemara.ControlCatalog)
+	}
+	if catalog == nil || catalog.Metadata.Id == "" {
+		return errors.New("catalog id cannot be empty")
+	}
+	if _, exists := v.referenceCatalogs[catalog.Metadata.Id]; exists {
+		return fmt.Errorf("duplicate catalog id found: %s", catalog.Metadata.Id)
+	}
+	v.referenceCatalogs[catalog.Metadata.Id] = catalog
+	v.addPossibleControls(catalog)
+	return nil
+}
+
 func (v *EvaluationOrchestrator) addPossibleControls(catalog *gemara.ControlCatalog) {
 	if v.possibleControls == nil {
 		v.possibleControls = make(map[string][]*gemara.Control)
@@ -128,13 +182,22 @@ func (v *EvaluationOrchestrator) addPossibleControls(catalog *gemara.ControlCata
 // their steps through a closure should register via AddEvaluationSuiteTyped so
 // the SDK captures each step's name before it is captured.
 func (v *EvaluationOrchestrator) AddEvaluationSuite(catalogId string, loader DataLoader, steps map[string][]gemara.AssessmentStep) error {
-	return v.addEvaluationSuiteNamed(catalogId, loader, steps, nil)
+	return v.addEvaluationSuiteNamed(catalogId, loader, registeredSteps{steps: steps})
+}
+
+// registeredSteps carries a suite's steps together with what the typed
+// registration helpers capture before adaptation. names and contextSteps are
+// keyed by requirement id and positionally parallel to steps.
+type registeredSteps struct {
+	steps        map[string][]gemara.AssessmentStep
+	names        map[string][]string      // nil falls back to symbol lookup
+	contextSteps map[string][]contextStep // nil for steps that take no context
+	payloadType  reflect.Type             // the steps' payload type; nil for untyped steps
 }
 
-// addEvaluationSuiteNamed is AddEvaluationSuite with optional step names,
-// supplied by the typed registration helpers. names is keyed by requirement id
-// and positionally parallel to steps; a nil map falls back to symbol lookup.
-func (v *EvaluationOrchestrator) addEvaluationSuiteNamed(catalogId string, loader DataLoader, steps map[string][]gemara.AssessmentStep, names map[string][]string) error {
+// addEvaluationSuiteNamed is AddEvaluationSuite with the optional step metadata
+// supplied by the typed registration helpers.
+func (v *EvaluationOrchestrator) addEvaluationSuiteNamed(catalogId string, loader DataLoader, registered registeredSteps) error {
 	if catalogId == "" {
 		return BAD_CATALOG(v.PluginName, "suite catalog id cannot be empty", "aos10")
 	}
@@ -145,7 +208,7 @@ func (v *EvaluationOrchestrator) addEvaluationSuiteNamed(catalogId string, loade
 		if catalog.Metadata.Id == "" {
 			return BAD_CATALOG(v.PluginName, "no id found in catalog metadata", "aos30")
 		}
-		v.addEvaluationSuite(catalog, loader, steps, names)
+		v.addEvaluationSuite(catalog, loader, registered)
 		return nil
 	}
 	return BAD_CATALOG(v.PluginName, fmt.Sprintf("no reference catalog found with id '%s'", catalogId), "aos40")
@@ -167,7 +230,7 @@ func (v *EvaluationOrchestrator) AddEvaluationSuiteForAllCatalogs(loader DataLoa
 	return nil
 }
 
-func (v *EvaluationOrchestrator) addEvaluationSuite(catalog *gemara.ControlCatalog, loader DataLoader, steps map[string][]gemara.AssessmentStep, names map[string][]string) {
+func (v *EvaluationOrchestrator) addEvaluationSuite(catalog *gemara.ControlCatalog, loader DataLoader, registered registeredSteps) {
 	for _, existing := range v.possibleSuites {
 		if existing.CatalogId == catalog.Metadata.Id {
 			return
@@ -187,11 +250,13 @@ func (v *EvaluationOrchestrator) addEvaluationSuite(catalog *gemara.ControlCatal
 	}
 
 	suite := EvaluationSuite{
-		CatalogId: catalog.Metadata.Id,
-		catalog:   suiteCatalog,
-		steps:     steps,
-		stepNames: names,
-		config:    v.config,
+		CatalogId:    catalog.Metadata.Id,
+		catalog:      suiteCatalog,
+		steps:        registered.steps,
+		stepNames:    registered.names,
+		contextSteps: registered.contextSteps,
+		payloadType:  registered.payloadType,
+		config:       v.config,
 	}
 
 	// Leave suite.loader nil when no override is given so loadPayload
@@ -228,6 +293,13 @@ func getImportedControls(catalog *gemara.ControlCatalog, referenceCatalogs map[s
 
 // Mobilize initializes the orchestrator and executes all evaluation suites.
 func (v *EvaluationOrchestrator) Mobilize() error {
+	return v.MobilizeContext(context.Background())
+}
+
+// MobilizeContext is Mobilize bounded by ctx and the configured run-timeout.
+// Steps still pending when the run ends are recorded as Unknown rather than
+// abandoning the suite, so the results written afterwards remain complete.
+func (v *EvaluationOrchestrator) MobilizeContext(ctx context.Context) error {
 	v.Evaluation_Suites = nil
 	v.setupConfig()
 	if v.config.Error != nil {
@@ -238,6 +310,12 @@ func (v *EvaluationOrchestrator) Mobilize() error {
 		return BAD_CONFIG(v.config.Error, "mob20")
 	}
 
+	if v.config.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.config.RunTimeout)
+		defer cancel()
+	}
+
 	// Init before loadPayload so retrieval is timed; nil when off.
 	var benchmarkStart time.Time
 	if v.config.Benchmark {
@@ -249,10 +327,16 @@ func (v *EvaluationOrchestrator) Mobilize() error {
 		}
 	}
 
-	err := v.loadPayload()
-	if err != nil {
+	err := v.loadPayload(ctx)
+	if err != nil && ctx.Err() == nil {
 		return BAD_LOADER(v.PluginName, err, "mob30")
 	}
+	if err != nil {
+		// Evaluating without a payload starts no step once the run has ended,
+		// so every requirement is recorded as Unknown with the deadline and
+		// the results written still cover the whole policy.
+		v.config.Logger.Warn("run ended before the payload was loaded; every requirement is recorded as Unknown", "reason", err)
+	}
 
 	v.ServiceName = v.config.ServiceName
 
@@ -270,6 +354,16 @@ func (v *EvaluationOrchestrator) Mobilize() error {
 		return NO_EVALUATION_SUITES("mob50")
 	}
 
+	testSuite, err := v.selectTestSuite()
+	if err != nil {
+		return err
+	}
+
+	journal, err := v.openChangeJournal()
+	if err != nil {
+		return CHANGE_JOURNAL_FAILED(err, "mob45")
+	}
+
 	availableCatalogIDs := make([]string, 0, len(v.possibleSuites))
 	for _, suite := range v.possibleSuites {
 		availableCatalogIDs = append(availableCatalogIDs, suite.CatalogId)
@@ -280,7 +374,13 @@ func (v *EvaluationOrchestrator) Mobilize() error {
 		for _, suite := range v.possibleSuites {
 			if suite.CatalogId == catalog {
 				matched = true
-				err := suite.Evaluate(v.ServiceName)
+				suite.testSuite, suite.testSuiteRequirements = v.TestSuite, testSuite
+				suite.retryPolicy, suite.requirementRetries = v.retryPolicy, v.requirementRetries
+				if cm, ok := v.changeManagers[catalog]; ok {
+					cm.journal, cm.catalogId = journal, catalog
+					suite.AddChangeManager(cm)
+				}
+				err := suite.EvaluateContext(ctx, v.ServiceName)
 				if err != nil {
 					v.config.Logger.Error(err.Error())
 				}
@@ -294,13 +394,17 @@ func (v *EvaluationOrchestrator) Mobilize() error {
 		}
 	}
 
+	if ctx.Err() != nil {
+		v.config.Logger.Warn("run ended before every step completed; unfinished steps are recorded as Unknown", "reason", ctx.Err())
+	}
+
 	if len(v.Evaluation_Suites) == 0 {
 		return NO_MATCHING_CATALOGS(v.config.Policy.ControlCatalogs, availableCatalogIDs, "mob60")
 	}
 
 	v.config.Logger.Trace("Mobilization complete")
 
-	if !v.config.Write {
+	if !v.config.Write && v.config.OutputDestination != config.StdoutDestination {
 		// Do not write results if the user has blocked it
 		return v.finalizeBenchmark(benchmarkStart)
 	}
@@ -373,7 +477,8 @@ func (v *EvaluationOrchestrator) stampEvaluationLog(suite *EvaluationSuite) {
 	suite.EvaluationLog.Target = target
 }
 
-// WriteResults writes the evaluation results to files in the configured output format.
+// WriteResults writes the evaluation results to one file per configured output
+// format, or to stdout when the output destination is stdout.
 func (v *EvaluationOrchestrator) WriteResults() error {
 
 	// The orchestrator's Payload is typically very large and is only useful for tracing.
@@ -388,9 +493,30 @@ func (v *EvaluationOrchestrator) WriteResults() error {
 		defer func() { v.Payload = saved }()
 	}
 
-	var err error
-	var result []byte
-	switch v.config.Output {
+	// Each format is marshaled and written independently, so one that fails
+	// does not stop the others from being written.
+	var failures []string
+	for _, output := range v.config.Output {
+		result, err := v.marshalResults(output)
+		if err == nil && v.config.OutputDestination == config.StdoutDestination {
+			err = errMod(v.writeResultsToStdout(result), "wr50")
+		} else if err == nil {
+			err = errMod(v.writeResultsToFile(v.ServiceName, result, output), "wr60")
+		}
+		if err != nil {
+			v.config.Logger.Error("Results were not written", "output", output, "error", err)
+			failures = append(failures, fmt.Sprintf("%s: %s", output, err))
+		}
+	}
+	if len(failures) > 0 {
+		return WRITE_FAILED(v.PluginName, strings.Join(failures, "; "), "wr40")
+	}
+	return nil
+}
+
+// marshalResults serializes the run in a single output format.
+func (v *EvaluationOrchestrator) marshalResults(output string) (result []byte, err error) {
+	switch output {
 	case "json":
 		result, err = json.Marshal(v)
 		err = errMod(err, "wr10")
@@ -404,28 +530,29 @@ func (v *EvaluationOrchestrator) WriteResults() error {
 		// Privateer's orchestrator envelope.
 		result, err = v.marshalGemara()
 		err = errMod(err, "wr25")
+	case "html":
+		result, err = v.marshalHTML()
+		err = errMod(err, "wr27")
+	case "junit":
+		result, err = v.marshalJUnit()
+		err = errMod(err, "wr28")
+	case "oscal":
+		result, err = v.marshalOSCAL()
+		err = errMod(err, "wr29")
 	case "sarif":
 		for _, suite := range v.Evaluation_Suites {
-			evalConverter := gemaraconv.EvaluationLog(suite.EvaluationLog)
-			sarifBytes, sarifErr := evalConverter.ToSARIF(gemaraconv.WithCatalog(suite.catalog))
+			sarifBytes, sarifErr := suite.marshalSARIF()
 			if sarifErr != nil {
+				err = errMod(sarifErr, "wr35")
 				break
 			}
 			result = append(result, sarifBytes...)
 		}
 	default:
-		err = fmt.Errorf("output type '%s' is not supported. Supported types are 'json', 'yaml', 'sarif', and 'gemara'", v.config.Output)
+		err = fmt.Errorf("output type '%s' is not supported. Supported types are 'json', 'yaml', 'sarif', 'gemara', 'html', 'junit', and 'oscal'", output)
 		err = errMod(err, "wr30")
 	}
-	if err != nil {
-		return WRITE_FAILED(v.PluginName, err.Error(), "wr40")
-	}
-	err = v.writeResultsToFile(v.ServiceName, result, v.config.Output)
-	if err != nil {
-		return WRITE_FAILED(v.ServiceName, err.Error(), "wr60")
-	}
-
-	return nil
+	return result, err
 }
 
 // marshalGemara serializes the run as gemara-native EvaluationLog objects.
@@ -439,11 +566,33 @@ func (v *EvaluationOrchestrator) marshalGemara() ([]byte, error) {
 	return yaml.Marshal(logs)
 }
 
+// writeResultsToStdout streams results to the plugin's stdout, which go-plugin
+// forwards to the host's SyncStdout. os.Stdout is looked up at write time
+// because go-plugin replaces it with its own pipe when the plugin is served.
+func (v *EvaluationOrchestrator) writeResultsToStdout(result []byte) error {
+	out := v.stdout
+	if out == nil {
+		out = os.Stdout
+	}
+	if len(result) > 0 && result[len(result)-1] != '\n' {
+		result = append(result, '\n')
+	}
+	_, err := out.Write(result)
+	return err
+}
+
 func (v *EvaluationOrchestrator) writeResultsToFile(serviceName string, result []byte, extension string) error {
-	// gemara output is YAML-encoded; write it with a .yaml extension
-	// rather than a literal ".gemara" so tooling recognizes the format.
-	if extension == "gemara" {
-		extension = "yaml"
+	// gemara output is YAML-encoded, junit is XML and oscal is OSCAL JSON;
+	// write them with the extension tooling recognizes rather than the output
+	// type's name. gemara keeps its own name so it never overwrites the yaml
+	// results of the same run.
+	switch extension {
+	case "gemara":
+		extension = ".gemara.yaml"
+	case "junit":
+		extension = "xml"
+	case "oscal":
+		extension = ".oscal.json"
 	}
 	if !strings.Contains(extension, ".") {
 		extension = fmt.Sprintf(".%s", extension)
@@ -481,12 +630,29 @@ func (v *EvaluationOrchestrator) writeResultsToFile(serviceName string, result [
 	return nil
 }
 
-// loadPayload loads the payload data to be referenced in assessments.
-func (v *EvaluationOrchestrator) loadPayload() (err error) {
+// loadPayload loads the payload data to be referenced in assessments. In
+// record mode every loaded payload is also saved to the fixture file; in
+// replay mode the loaders are skipped and the fixture is decoded instead.
+// Otherwise, when the payload cache is on, cached payloads stand in for the
+// loaders that produced them.
+func (v *EvaluationOrchestrator) loadPayload(ctx context.Context) (err error) {
+	var fixture *payloadFixture
+	var cache *payloadCache
+	if v.config.ReplayPayload != "" {
+		if fixture, err = readPayloadFixture(v.config.ReplayPayload); err != nil {
+			return err
+		}
+		v.config.Logger.Info("Replaying recorded payloads instead of running loaders",
+			"fixture", v.config.ReplayPayload, "recorded-at", fixture.RecordedAt)
+	} else {
+		if v.config.RecordPayload != "" {
+			fixture = v.newPayloadFixture()
+		}
+		cache = v.openPayloadCache()
+	}
+
 	if v.loader != nil {
-		start := time.Now()
-		data, err := v.loader(v.config)
-		v.recordLoader("orchestrator", v.loader, time.Since(start))
+		data, err := v.load(ctx, "orchestrator", v.loader, v.orchestratorPayloadType(), fixture, cache)
 		if err != nil {
 			return err
 		}
@@ -494,9 +660,11 @@ func (v *EvaluationOrchestrator) loadPayload() (err error) {
 	}
 	for _, suite := range v.possibleSuites {
 		if suite.loader != nil {
-			start := time.Now()
-			data, err := suite.loader(v.config)
-			v.recordLoader("suite:"+suite.CatalogId, suite.loader, time.Since(start))
+			payloadType := suite.payloadType
+			if payloadType == nil {
+				payloadType = v.payloadType
+			}
+			data, err := v.load(ctx, "suite:"+suite.CatalogId, suite.loader, payloadType, fixture, cache)
 			if err != nil {
 				return err
 			}
@@ -505,9 +673,70 @@ func (v *EvaluationOrchestrator) loadPayload() (err error) {
 			suite.payload = v.Payload
 		}
 	}
+
+	if cache != nil {
+		cache.save(v)
+	}
+	if v.config.RecordPayload != "" {
+		if err := fixture.write(v.config.RecordPayload); err != nil {
+			return err
+		}
+		v.config.Logger.Info("Recorded loader payloads", "fixture", v.config.RecordPayload)
+	}
 	return nil
 }
 
+// load runs one loader, or in replay mode decodes its recorded payload, or
+// takes it from the payload cache.
+func (v *EvaluationOrchestrator) load(ctx context.Context, scope string, loader DataLoader, payloadType reflect.Type, fixture *payloadFixture, cache *payloadCache) (any, error) {
+	start := time.Now()
+	if v.config.ReplayPayload != "" {
+		data, err := fixture.replay(scope, payloadType)
+		v.recordLoader(scope, loader, time.Since(start), LoaderSourceReplay)
+		return data, err
+	}
+
+	var data any
+	var err error
+	if cached, ok := cache.get(v, scope, payloadType); ok {
+		data = cached
+		v.recordLoader(scope, loader, time.Since(start), LoaderSourceCache)
+	} else {
+		data, err = v.callLoader(ctx, loader)
+		v.recordLoader(scope, loader, time.Since(start), "")
+		if err == nil && cache != nil {
+			cache.put(v, scope, data, payloadType)
+		}
+	}
+	if err == nil && fixture != nil {
+		err = fixture.record(scope, data)
+	}
+	return data, err
+}
+
+// callLoader runs loader until it returns or ctx ends. DataLoader takes no
+// context, so a loader still running at the deadline is abandoned, not stopped.
+func (v *EvaluationOrchestrator) callLoader(ctx context.Context, loader DataLoader) (any, error) {
+	if ctx.Done() == nil {
+		return loader(v.config)
+	}
+	type loaded struct {
+		data any
+		err  error
+	}
+	done := make(chan loaded, 1)
+	go func() {
+		data, err := loader(v.config)
+		done <- loaded{data, err}
+	}()
+	select {
+	case l := <-done:
+		return l.data, l.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("payload loader did not complete: %w", ctx.Err())
+	}
+}
+
 func (v *EvaluationOrchestrator) setupConfig() {
 	if v.config == nil {
 		c := config.NewConfig(v.requiredVars)
@@ -1,6 +1,7 @@
 package pluginkit
 
 import (
+	"bytes"
 	"embed"
 	"encoding/json"
 	"os"
@@ -98,6 +99,40 @@ func TestEvaluationOrchestrator_AddReferenceCatalogs(t *testing.T) {
 	})
 }
 
+func TestEvaluationOrchestrator_AddReferenceCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog *gemara.ControlCatalog
+		wantErr string
+	}{
+		{name: "in-memory catalog", catalog: getTestCatalogWithID("CCC.Inline")},
+		{name: "nil catalog", catalog: nil, wantErr: "catalog id cannot be empty"},
+		{name: "missing id", catalog: getEmptyTestCatalog(), wantErr: "catalog id cannot be empty"},
+		{name: "duplicate id", catalog: getTestCatalogWithRequirements(), wantErr: "duplicate catalog id found: CCC.ObjStor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orchestrator := &EvaluationOrchestrator{}
+			if err := orchestrator.AddReferenceCatalog(getTestCatalogWithRequirements()); err != nil {
+				t.Fatalf("adding the first catalog: %v", err)
+			}
+			err := orchestrator.AddReferenceCatalog(tt.catalog)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, ok := orchestrator.possibleControls["CCC.Core.C01"]; !ok {
+					t.Error("expected the catalog's controls to be registered")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestEvaluationOrchestrator_AddEvaluationSuite(t *testing.T) {
 	t.Run("Error Without Reference Catalogs", func(t *testing.T) {
 		orchestrator := &EvaluationOrchestrator{}
@@ -158,7 +193,7 @@ func TestEvaluationOrchestrator_Mobilize_BreaksAfterMatch(t *testing.T) {
 	cfg.Policy.ControlCatalogs = []string{"CCC.ObjStor"}
 	cfg.Write = true
 	cfg.WriteDirectory = tmpDir
-	cfg.Output = "yaml"
+	cfg.Output = []string{"yaml"}
 
 	catalog := getTestCatalogWithRequirements()
 	steps := createPassingStepsMap()
@@ -370,7 +405,7 @@ func TestEvaluationOrchestrator_Mobilize_UnmatchedCatalogs(t *testing.T) {
 		cfg := setBasicConfig()
 		cfg.Write = true
 		cfg.WriteDirectory = tmpDir
-		cfg.Output = "yaml"
+		cfg.Output = []string{"yaml"}
 
 		catalog := getTestCatalogWithRequirements()
 		steps := createPassingStepsMap()
@@ -423,7 +458,7 @@ func TestEvaluationOrchestrator_Mobilize_UnmatchedCatalogs(t *testing.T) {
 		cfg.Policy.ControlCatalogs = []string{"CCC.ObjStor", "nonexistent-catalog"}
 		cfg.Write = true
 		cfg.WriteDirectory = tmpDir
-		cfg.Output = "yaml"
+		cfg.Output = []string{"yaml"}
 
 		catalog := getTestCatalogWithRequirements()
 		steps := createPassingStepsMap()
@@ -577,7 +612,7 @@ func TestEvaluationOrchestrator_WriteResults_SARIF(t *testing.T) {
 			}()
 
 			cfg := setBasicConfig()
-			cfg.Output = "sarif"
+			cfg.Output = []string{"sarif"}
 			cfg.Write = true
 			cfg.WriteDirectory = tmpDir
 
@@ -663,7 +698,7 @@ func writeResultsFixture(t *testing.T, output string, includePayload bool, paylo
 	t.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
 
 	cfg := setBasicConfig()
-	cfg.Output = output
+	cfg.Output = []string{output}
 	cfg.Write = true
 	cfg.WriteDirectory = tmpDir
 	cfg.IncludePayload = includePayload
@@ -688,11 +723,16 @@ func writeResultsFixture(t *testing.T, output string, includePayload bool, paylo
 		t.Fatalf("WriteResults failed: %v", err)
 	}
 
-	// gemara output is written with a .yaml extension; everything else uses the
-	// configured output name directly.
+	// gemara, junit and oscal output are written with .gemara.yaml, .xml and
+	// .oscal.json extensions; everything else uses the configured output name.
 	ext := output
-	if ext == "gemara" {
-		ext = "yaml"
+	switch ext {
+	case "gemara":
+		ext = "gemara.yaml"
+	case "junit":
+		ext = "xml"
+	case "oscal":
+		ext = "oscal.json"
 	}
 	return filepath.Join(tmpDir, "test-service", "test-service."+ext)
 }
@@ -741,7 +781,7 @@ func TestEvaluationOrchestrator_WriteResults_Gemara(t *testing.T) {
 		t.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
 
 		cfg := setBasicConfig()
-		cfg.Output = "gemara"
+		cfg.Output = []string{"gemara"}
 		cfg.Write = true
 		cfg.WriteDirectory = tmpDir
 
@@ -754,7 +794,7 @@ func TestEvaluationOrchestrator_WriteResults_Gemara(t *testing.T) {
 			t.Fatalf("WriteResults failed: %v", err)
 		}
 
-		data, err := os.ReadFile(filepath.Join(tmpDir, "test-service", "test-service.yaml"))
+		data, err := os.ReadFile(filepath.Join(tmpDir, "test-service", "test-service.gemara.yaml"))
 		if err != nil {
 			t.Fatalf("read output: %v", err)
 		}
@@ -766,6 +806,89 @@ func TestEvaluationOrchestrator_WriteResults_Gemara(t *testing.T) {
 	})
 }
 
+func TestEvaluationOrchestrator_WriteResults_MultipleOutputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := setBasicConfig()
+	cfg.Write = true
+	cfg.WriteDirectory = tmpDir
+	// oscal requires at least one suite, so it fails here while the others succeed
+	cfg.Output = []string{"yaml", "oscal", "json"}
+	orchestrator := &EvaluationOrchestrator{ServiceName: "test-service", PluginName: "test-plugin", config: cfg}
+
+	err := orchestrator.WriteResults()
+	if err == nil {
+		t.Fatal("expected an error for the oscal output")
+	}
+	if !strings.Contains(err.Error(), "oscal: ") || strings.Contains(err.Error(), "yaml: ") || strings.Contains(err.Error(), "json: ") {
+		t.Errorf("expected only the oscal output to be reported, got %v", err)
+	}
+	for _, name := range []string{"test-service.yaml", "test-service.json"} {
+		if _, statErr := os.Stat(filepath.Join(tmpDir, "test-service", name)); statErr != nil {
+			t.Errorf("expected %s to be written despite the oscal failure: %v", name, statErr)
+		}
+	}
+	if _, statErr := os.Stat(filepath.Join(tmpDir, "test-service", "test-service.oscal.json")); !os.IsNotExist(statErr) {
+		t.Errorf("expected no oscal file, got %v", statErr)
+	}
+}
+
+func TestEvaluationOrchestrator_WriteResults_YamlAndGemara(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := setBasicConfig()
+	cfg.Write = true
+	cfg.WriteDirectory = tmpDir
+	cfg.Output = []string{"yaml", "gemara"}
+	orchestrator := &EvaluationOrchestrator{
+		ServiceName:       "test-service",
+		PluginName:        "test-plugin",
+		config:            cfg,
+		Evaluation_Suites: []*EvaluationSuite{{CatalogId: "test-catalog", EvaluationLog: createTestEvalLog(), config: cfg}},
+	}
+
+	if err := orchestrator.WriteResults(); err != nil {
+		t.Fatalf("WriteResults failed: %v", err)
+	}
+	envelope, err := os.ReadFile(filepath.Join(tmpDir, "test-service", "test-service.yaml"))
+	if err != nil || !strings.Contains(string(envelope), "service-name: test-service") {
+		t.Errorf("expected the yaml envelope to survive the gemara output, got %v:\n%s", err, envelope)
+	}
+	logs, err := os.ReadFile(filepath.Join(tmpDir, "test-service", "test-service.gemara.yaml"))
+	if err != nil || !strings.HasPrefix(string(logs), "-") {
+		t.Errorf("expected the gemara list in its own file, got %v:\n%s", err, logs)
+	}
+}
+
+func TestEvaluationOrchestrator_WriteResults_Stdout(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := setBasicConfig()
+	cfg.Write = true
+	cfg.WriteDirectory = tmpDir
+	cfg.Output = []string{"json"}
+	cfg.OutputDestination = config.StdoutDestination
+	var stdout bytes.Buffer
+	orchestrator := &EvaluationOrchestrator{
+		ServiceName:       "test-service",
+		PluginName:        "test-plugin",
+		config:            cfg,
+		stdout:            &stdout,
+		Evaluation_Suites: []*EvaluationSuite{{CatalogId: "test-catalog", EvaluationLog: createTestEvalLog(), config: cfg}},
+	}
+
+	if err := orchestrator.WriteResults(); err != nil {
+		t.Fatalf("WriteResults failed: %v", err)
+	}
+	var streamed map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &streamed); err != nil {
+		t.Fatalf("expected stdout to hold only the json results: %v\n%s", err, stdout.String())
+	}
+	if streamed["service-name"] != "test-service" || !strings.HasSuffix(stdout.String(), "\n") {
+		t.Errorf("unexpected stream: %q", stdout.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "test-service", "test-service.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no results file when streaming, got %v", err)
+	}
+}
+
 func TestEvaluationOrchestrator_StampEvaluationLog(t *testing.T) {
 	newOrchestrator := func() *EvaluationOrchestrator {
 		return &EvaluationOrchestrator{
@@ -911,7 +1034,7 @@ func TestEvaluationOrchestrator_WriteResults_IncludePayload(t *testing.T) {
 		t.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
 
 		cfg := setBasicConfig()
-		cfg.Output = "yaml"
+		cfg.Output = []string{"yaml"}
 		cfg.Write = true
 		cfg.WriteDirectory = tmpDir
 		cfg.IncludePayload = false
@@ -1,12 +1,18 @@
 package pluginkit
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gemaraproj/go-gemara"
 	"github.com/privateerproj/privateer-sdk/config"
+	"golang.org/x/sync/errgroup"
 )
 
 // TestSet is a function type that returns a control evaluation result.
@@ -22,7 +28,10 @@ type EvaluationSuite struct {
 	StartTime string `json:"start-time" yaml:"start-time"` // StartTime is the time the plugin started
 	EndTime   string `json:"end-time" yaml:"end-time"`     // EndTime is the time the plugin ended
 
-	CorruptedState bool `json:"corrupted-state" yaml:"corrupted-state"` // CorruptedState is true if any testSet failed to revert at the end of the evaluation
+	CorruptedState bool           `json:"corrupted-state" yaml:"corrupted-state"`     // CorruptedState is true if any testSet failed to revert at the end of the evaluation
+	Reverts        []ChangeRevert `json:"reverts,omitempty" yaml:"reverts,omitempty"` // Reverts reports each change of an invasive run and how it was reverted
+
+	Waivers []WaivedAssessment `json:"waivers,omitempty" yaml:"waivers,omitempty"` // Waivers lists the configured waivers that matched an assessment
 
 	EvaluationLog gemara.EvaluationLog `json:"control-evaluations" yaml:"control-evaluations"` // EvaluationLog is a slice of evaluations to be executed
 
@@ -34,13 +43,26 @@ type EvaluationSuite struct {
 	catalog       *gemara.ControlCatalog             // The Catalog this evaluation suite references
 	steps         map[string][]gemara.AssessmentStep // steps is a map of control IDs to their assessment steps
 	stepNames     map[string][]string                // step names captured at registration, parallel to steps; nil falls back to symbol lookup
+	contextSteps  map[string][]contextStep           // context-carrying steps, parallel to steps; nil when none were registered
+	payloadType   reflect.Type                       // payload type of typed steps, for replay; nil when untyped
+	runCtx        context.Context                    // runCtx bounds the run while Evaluate is in progress
+
+	testSuite             string          // the named test suite being run
+	testSuiteRequirements map[string]bool // requirement ids in testSuite; nil runs every requirement
+
+	retryPolicy        RetryPolicy            // retries for every step
+	requirementRetries map[string]RetryPolicy // per-requirement overrides of retryPolicy
+
+	prerequisites map[string][]string // requirement ids each requirement depends on; see AddPrerequisites
 
 	evalSuccesses int // successes is the number of successful evaluations
 	evalFailures  int // failures is the number of failed evaluations
 	evalWarnings  int // warnings is the number of evaluations that need review
+	evalWaived    int // waived is the number of evaluations that only fail or need review through actively waived assessments
 
-	durationNs  int64        // benchmark mode only
-	stepTimings []StepTiming // benchmark mode only
+	durationNs  int64        // benchmark mode and junit output only
+	stepTimings []StepTiming // benchmark mode and junit output only
+	timingsMu   sync.Mutex   // guards stepTimings when evaluations run concurrently
 }
 
 // AddChangeManager sets up the change manager for the evaluation suite.
@@ -54,6 +76,15 @@ func (e *EvaluationSuite) AddChangeManager(cm *ChangeManager) {
 // Evaluate executes a list of EvaluationLog provided by a Plugin and customized by user config.
 // Name is an arbitrary string that will be used to identify the EvaluationSuite.
 func (e *EvaluationSuite) Evaluate(serviceName string) error {
+	return e.EvaluateContext(context.Background(), serviceName)
+}
+
+// EvaluateContext is Evaluate bounded by ctx: steps that have not completed
+// when ctx ends are recorded as Unknown.
+func (e *EvaluationSuite) EvaluateContext(ctx context.Context, serviceName string) error {
+	e.runCtx = ctx
+	defer func() { e.runCtx = nil }()
+
 	if e.config == nil {
 		return CONFIG_NOT_INITIALIZED("ev10")
 	}
@@ -74,33 +105,57 @@ func (e *EvaluationSuite) Evaluate(serviceName string) error {
 
 	e.Name = fmt.Sprintf("%s_%s", serviceName, e.CatalogId)
 	e.EvaluationLog = evalLog
+	e.orderByPrerequisites()
 	started := time.Now()
 	e.StartTime = started.UTC().Format(time.RFC3339Nano)
-	if e.config.Benchmark {
+	if e.timesSteps() {
 		// duration from the monotonic clock, never timestamp subtraction
 		defer func() { e.durationNs = time.Since(started).Nanoseconds() }()
 	}
 
 	e.config.Logger.Trace("Starting evaluation", "name", e.Name, "time", e.StartTime)
 
+	// In parallel mode, or when prerequisites set the order, every evaluation
+	// has already run by the time the loop below starts, so aggregation and
+	// logging still happen in catalog order.
+	evaluated := e.evaluateConcurrently() || e.evaluateInDependencyOrder()
+
+	waiverTime := time.Now()
 	for _, evaluation := range e.EvaluationLog.Evaluations {
-		evaluation.Evaluate(e.payload, e.config.Policy.Applicability)
+		if !evaluated {
+			evaluation.Evaluate(e.payload, e.config.Policy.Applicability)
+		}
 
 		// Make sure the evaluation result is updated based on the complete assessment results
 		e.Result = gemara.UpdateAggregateResult(e.Result, evaluation.Result)
 
 		// Log each assessment result as a separate line
+		unwaived, anyWaived := gemara.NotRun, false
 		for _, assessment := range evaluation.AssessmentLogs {
+			if assessment.Result == gemara.NotRun && assessment.Message == "" && len(e.prerequisites[assessment.Requirement.EntryId]) > 0 {
+				// a dependent left unrun without a message is explained by the prerequisite it waited on
+				if reason := e.unmetPrerequisite(assessment.Requirement.EntryId); reason != "" {
+					assessment.Message = reason
+				}
+			}
+			waived := e.applyWaiver(evaluation.Control.EntryId, assessment, waiverTime)
+			if waived {
+				anyWaived = true
+			} else {
+				unwaived = gemara.UpdateAggregateResult(unwaived, assessment.Result)
+			}
 			message := fmt.Sprintf("%s: %s", assessment.Requirement.EntryId, singleLine(assessment.Message))
 			// switch case the code below
-			switch assessment.Result {
-			case gemara.Passed:
+			switch {
+			case waived:
+				e.config.Logger.Warn(message)
+			case assessment.Result == gemara.Passed:
 				e.config.Logger.Info(message)
-			case gemara.NeedsReview:
+			case assessment.Result == gemara.NeedsReview:
 				e.config.Logger.Warn(message)
-			case gemara.Failed:
+			case assessment.Result == gemara.Failed:
 				e.config.Logger.Error(message)
-			case gemara.Unknown:
+			case assessment.Result == gemara.Unknown:
 				e.config.Logger.Error(message)
 			}
 
@@ -109,26 +164,37 @@ func (e *EvaluationSuite) Evaluate(serviceName string) error {
 			}
 		}
 
-		if evaluation.Result == gemara.Passed {
+		// An evaluation is counted by what is left once waived assessments
+		// are set aside, and as waived when nothing left fails or needs review.
+		result := evaluation.Result
+		if anyWaived {
+			result = unwaived
+		}
+		switch {
+		case anyWaived && (result == gemara.Passed || result == gemara.NotRun || result == gemara.NotApplicable):
+			e.evalWaived += 1
+		case result == gemara.Passed:
 			e.evalSuccesses += 1
-		} else if evaluation.Result == gemara.Failed {
+		case result == gemara.Failed:
 			e.evalFailures += 1
-		} else if evaluation.Result != gemara.NotRun {
+		case result != gemara.NotRun:
 			e.evalWarnings += 1
 		}
-		if e.changeManager != nil && e.changeManager.CorruptedState {
+		// Evaluations run ahead of this loop already stopped at a corrupted
+		// state and left the rest NotRun, so every one is still aggregated.
+		if !evaluated && e.changeManager != nil && e.changeManager.CorruptedState {
 			break
 		}
 	}
 
-	output := fmt.Sprintf("> %s: %v Passed, %v Warnings, %v Failed, %v Possible", e.Name, e.evalSuccesses, e.evalWarnings, e.evalFailures, len(evalLog.Evaluations))
+	output := fmt.Sprintf("> %s: %v Passed, %v Warnings, %v Failed, %v Waived, %v Possible", e.Name, e.evalSuccesses, e.evalWarnings, e.evalFailures, e.evalWaived, len(evalLog.Evaluations))
 
 	e.restoreSteps()
 
 	e.EndTime = time.Now().UTC().Format(time.RFC3339Nano)
 
 	if e.changeManager != nil {
-		e.changeManager.RevertAll()
+		e.Reverts = e.changeManager.RevertAll()
 		// The ChangeManager tracks corruption per change; sync it onto the suite
 		// so the written results (and the gemara EvaluationLog) can report it.
 		e.CorruptedState = e.changeManager.CorruptedState
@@ -137,7 +203,7 @@ func (e *EvaluationSuite) Evaluate(serviceName string) error {
 		}
 	}
 
-	switch e.Result {
+	switch e.unwaivedResult() {
 	case gemara.Passed:
 		e.config.Logger.Info(output)
 	case gemara.NotRun:
@@ -148,14 +214,94 @@ func (e *EvaluationSuite) Evaluate(serviceName string) error {
 	return nil
 }
 
-// restoreSteps restores benchmark-wrapped steps back to the original for stack tracing
-func (e *EvaluationSuite) restoreSteps() {
-	if e.config == nil || !e.config.Benchmark {
+// workers returns the number of control evaluations this suite may run at
+// once. Invasive runs stay serial: changes applied by one step may be observed
+// or reverted by another, and a corrupted state must halt the remaining
+// evaluations before they start. Payloads that collect evidence through
+// gemara.HasEvidence also stay serial, because that interface has a single
+// shared location that concurrent steps would overwrite. Suites with
+// prerequisites stay serial so each requirement runs after those it needs.
+func (e *EvaluationSuite) workers() int {
+	if e.config == nil || e.config.EvaluationWorkers <= 1 {
+		return 1
+	}
+	if e.changeManager != nil {
+		e.config.Logger.Debug("change manager attached; evaluating serially", "name", e.Name)
+		return 1
+	}
+	if _, ok := e.payload.(gemara.HasEvidence); ok {
+		e.config.Logger.Debug("payload collects evidence; evaluating serially", "name", e.Name)
+		return 1
+	}
+	if len(e.prerequisites) > 0 {
+		e.config.Logger.Debug("requirements have prerequisites; evaluating serially", "name", e.Name)
+		return 1
+	}
+	return e.config.EvaluationWorkers
+}
+
+// evaluateConcurrently runs every control evaluation in a bounded worker pool
+// and reports whether it did so. Each evaluation only writes to its own
+// ControlEvaluation, so results are identical to a serial run; steps must
+// treat the shared payload as read-only.
+func (e *EvaluationSuite) evaluateConcurrently() bool {
+	workers := e.workers()
+	if workers <= 1 || len(e.EvaluationLog.Evaluations) <= 1 {
+		return false
+	}
+	e.config.Logger.Trace("Evaluating concurrently", "name", e.Name, "workers", workers)
+
+	var g errgroup.Group
+	g.SetLimit(workers)
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		g.Go(func() error {
+			evaluation.Evaluate(e.payload, e.config.Policy.Applicability)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	e.sortStepTimings()
+	return true
+}
+
+// sortStepTimings puts concurrently recorded step timings back into catalog
+// order so the benchmark report does not depend on goroutine scheduling.
+func (e *EvaluationSuite) sortStepTimings() {
+	if len(e.stepTimings) == 0 {
 		return
 	}
+	position := make(map[string]int)
 	for _, evaluation := range e.EvaluationLog.Evaluations {
 		for _, assessment := range evaluation.AssessmentLogs {
-			assessment.Steps = e.steps[assessment.Requirement.EntryId]
+			if _, seen := position[assessment.Requirement.EntryId]; !seen {
+				position[assessment.Requirement.EntryId] = len(position)
+			}
+		}
+	}
+	sort.SliceStable(e.stepTimings, func(i, j int) bool {
+		a, b := e.stepTimings[i], e.stepTimings[j]
+		if position[a.RequirementId] != position[b.RequirementId] {
+			return position[a.RequirementId] < position[b.RequirementId]
+		}
+		return a.StepIndex < b.StepIndex
+	})
+}
+
+// timesSteps reports whether the suite records its duration and step timings:
+// benchmark mode reports them, and the junit output uses them as testcase times.
+func (e *EvaluationSuite) timesSteps() bool {
+	return e.config != nil && (e.config.Benchmark || slices.Contains(e.config.Output, "junit"))
+}
+
+// restoreSteps restores benchmark- and deadline-wrapped steps back to the
+// originals, so the written results name the registered steps, not the wrappers.
+func (e *EvaluationSuite) restoreSteps() {
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		for _, assessment := range evaluation.AssessmentLogs {
+			if assessment.Steps != nil {
+				assessment.Steps = e.steps[assessment.Requirement.EntryId]
+			}
 		}
 	}
 }
@@ -173,7 +319,8 @@ func (e *EvaluationSuite) stepName(requirementId string, i int, step gemara.Asse
 }
 
 // timedSteps wraps each executed step in a closure that records its duration, name, and result.
-func (e *EvaluationSuite) timedSteps(controlId, requirementId string, steps []gemara.AssessmentStep) []gemara.AssessmentStep {
+// attempts is filled by retriedSteps as each step runs; nil means every step runs once.
+func (e *EvaluationSuite) timedSteps(controlId, requirementId string, steps []gemara.AssessmentStep, attempts []int) []gemara.AssessmentStep {
 	if len(steps) == 0 {
 		return steps
 	}
@@ -183,12 +330,19 @@ func (e *EvaluationSuite) timedSteps(controlId, requirementId string, steps []ge
 		timed[i] = func(payload interface{}) (gemara.Result, string, gemara.ConfidenceLevel) {
 			start := time.Now()
 			result, message, confidence := step(payload)
+			attempt := 1
+			if attempts != nil {
+				attempt = attempts[i]
+			}
+			e.timingsMu.Lock()
+			defer e.timingsMu.Unlock()
 			e.stepTimings = append(e.stepTimings, StepTiming{
 				ControlId:     controlId,
 				RequirementId: requirementId,
 				StepIndex:     i,
 				Step:          name,
 				Result:        result.String(),
+				Attempts:      attempt,
 				DurationNs:    time.Since(start).Nanoseconds(),
 			})
 			return result, message, confidence
@@ -219,6 +373,21 @@ func (e *EvaluationSuite) GetAssessmentRequirements() (map[string]*gemara.Assess
 	return requirements, nil
 }
 
+// selects reports whether the policy, the run's include/exclude filter, and
+// the selected test suite all keep the requirement, and if not, why.
+func (e *EvaluationSuite) selects(control gemara.Control, requirementId string) (bool, string) {
+	if selected, reason := e.config.Policy.Selects(e.CatalogId, control.Id, requirementId); !selected {
+		return false, reason
+	}
+	if selected, reason := e.config.Filter.Selects(control.Group, control.Id, requirementId); !selected {
+		return false, reason
+	}
+	if e.testSuiteRequirements != nil && !e.testSuiteRequirements[requirementId] {
+		return false, fmt.Sprintf("not in test suite %q", e.testSuite)
+	}
+	return true, ""
+}
+
 func (e *EvaluationSuite) setupEvalLog(steps map[string][]gemara.AssessmentStep) (evalLog gemara.EvaluationLog, err error) {
 	if len(steps) == 0 {
 		return evalLog, NO_ASSESSMENT_STEPS_PROVIDED("sel10")
@@ -250,20 +419,37 @@ func (e *EvaluationSuite) setupEvalLog(steps map[string][]gemara.AssessmentStep)
 		}
 
 		for _, requirement := range control.AssessmentRequirements {
-			// benchmark mode times each step; later on restoreSteps will unwrap this before serialization
-			reqSteps := steps[requirement.Id]
-			if e.config != nil && e.config.Benchmark {
-				reqSteps = e.timedSteps(control.Id, requirement.Id, reqSteps)
+			applicability := requirement.Applicability
+			if e.config != nil {
+				// requirements the policy or filter leaves out are recorded as not run, with the reason
+				if selected, reason := e.selects(control, requirement.Id); !selected {
+					evaluation.AssessmentLogs = append(evaluation.AssessmentLogs, &gemara.AssessmentLog{
+						Requirement: gemara.EntryMapping{EntryId: requirement.Id},
+						Description: control.Objective,
+						Result:      gemara.NotRun,
+						Message:     reason,
+					})
+					continue
+				}
+				applicability = e.config.Policy.RequirementApplicability(e.CatalogId, requirement.Id, applicability)
 			}
 
-			// Use AddAssessment instead of manual struct creation
+			// Use AddAssessment instead of manual struct creation. The assessment
+			// exists before its steps are wrapped, so they can attach evidence to it.
 			assessment := evaluation.AddAssessment(
-				requirement.Id,            // requirementId
-				control.Objective,         // description
-				requirement.Applicability, // applicability
-				reqSteps,                  // steps
+				requirement.Id,        // requirementId
+				control.Objective,     // description
+				applicability,         // applicability
+				steps[requirement.Id], // steps
 			)
 
+			// benchmark mode and junit output time each step; later on restoreSteps will unwrap this before serialization
+			reqSteps, attempts := e.retriedSteps(requirement.Id, e.deadlineSteps(requirement.Id, assessment.Steps, &assessment.Evidence), &assessment.Evidence)
+			if e.timesSteps() {
+				reqSteps = e.timedSteps(control.Id, requirement.Id, reqSteps, attempts)
+			}
+			assessment.Steps = e.gatedSteps(requirement.Id, reqSteps) // a step skipped for its prerequisites is not timed
+
 			// Handle case where no steps were found
 			if _, ok := steps[requirement.Id]; !ok {
 				assessment.Result = gemara.Unknown
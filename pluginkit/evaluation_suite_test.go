@@ -1,10 +1,12 @@
 package pluginkit
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/gemaraproj/go-gemara"
+	"github.com/privateerproj/privateer-sdk/config"
 )
 
 func BenchmarkEvaluate_Passing(b *testing.B) {
@@ -420,3 +422,196 @@ func TestEvaluationSuiteIntegration(t *testing.T) {
 		}
 	})
 }
+
+// getTestCatalogWithControls returns a catalog with n single-requirement controls,
+// so concurrency tests have more than one evaluation to schedule.
+func getTestCatalogWithControls(n int) *gemara.ControlCatalog {
+	catalog := &gemara.ControlCatalog{Metadata: gemara.Metadata{Id: "CCC.Parallel"}}
+	for i := 0; i < n; i++ {
+		controlId := fmt.Sprintf("CCC.Core.C%02d", i)
+		catalog.Controls = append(catalog.Controls, gemara.Control{
+			Id:        controlId,
+			Title:     controlId,
+			Objective: "Test objective",
+			AssessmentRequirements: []gemara.AssessmentRequirement{
+				{Id: controlId + ".TR01", Applicability: requestedApplicability},
+			},
+		})
+	}
+	return catalog
+}
+
+func TestEvaluateConcurrently(t *testing.T) {
+	catalog := getTestCatalogWithControls(8)
+	steps := make(map[string][]gemara.AssessmentStep)
+	for i, control := range catalog.Controls {
+		reqId := control.AssessmentRequirements[0].Id
+		switch i % 3 {
+		case 0:
+			steps[reqId] = []gemara.AssessmentStep{step_Pass, step_Pass}
+		case 1:
+			steps[reqId] = []gemara.AssessmentStep{step_Pass, step_Fail}
+		default:
+			steps[reqId] = []gemara.AssessmentStep{step_NeedsReview}
+		}
+	}
+
+	run := func(workers int) *EvaluationSuite {
+		suite := &EvaluationSuite{
+			CatalogId: catalog.Metadata.Id,
+			catalog:   catalog,
+			steps:     steps,
+			config:    setBasicConfig(),
+		}
+		suite.config.EvaluationWorkers = workers
+		suite.config.Benchmark = true
+		if err := suite.Evaluate("parallel"); err != nil {
+			t.Fatalf("Evaluate(workers=%d) failed: %v", workers, err)
+		}
+		return suite
+	}
+
+	serial := run(1)
+	parallel := run(4)
+
+	if parallel.Result != serial.Result {
+		t.Errorf("Result = %v, want %v", parallel.Result, serial.Result)
+	}
+	if parallel.evalSuccesses != serial.evalSuccesses || parallel.evalFailures != serial.evalFailures || parallel.evalWarnings != serial.evalWarnings {
+		t.Errorf("counts = %d/%d/%d, want %d/%d/%d",
+			parallel.evalSuccesses, parallel.evalWarnings, parallel.evalFailures,
+			serial.evalSuccesses, serial.evalWarnings, serial.evalFailures)
+	}
+	for i, evaluation := range parallel.EvaluationLog.Evaluations {
+		want := serial.EvaluationLog.Evaluations[i]
+		if evaluation.Control.EntryId != want.Control.EntryId || evaluation.Result != want.Result {
+			t.Errorf("evaluation %d = %s/%v, want %s/%v", i, evaluation.Control.EntryId, evaluation.Result, want.Control.EntryId, want.Result)
+		}
+	}
+	if len(parallel.stepTimings) != len(serial.stepTimings) {
+		t.Fatalf("recorded %d step timings, want %d", len(parallel.stepTimings), len(serial.stepTimings))
+	}
+	for i, timing := range parallel.stepTimings {
+		want := serial.stepTimings[i]
+		if timing.RequirementId != want.RequirementId || timing.StepIndex != want.StepIndex {
+			t.Errorf("step timing %d = %s[%d], want %s[%d]", i, timing.RequirementId, timing.StepIndex, want.RequirementId, want.StepIndex)
+		}
+	}
+}
+
+func TestEvaluationWorkers(t *testing.T) {
+	t.Run("Serial By Default", func(t *testing.T) {
+		suite := &EvaluationSuite{config: setBasicConfig()}
+		if got := suite.workers(); got != 1 {
+			t.Errorf("workers() = %d, want 1", got)
+		}
+	})
+
+	t.Run("Configured Workers", func(t *testing.T) {
+		suite := &EvaluationSuite{config: setBasicConfig()}
+		suite.config.EvaluationWorkers = 4
+		if got := suite.workers(); got != 4 {
+			t.Errorf("workers() = %d, want 4", got)
+		}
+	})
+
+	t.Run("Change Manager Forces Serial", func(t *testing.T) {
+		suite := &EvaluationSuite{config: setBasicConfig()}
+		suite.config.EvaluationWorkers = 4
+		suite.config.Invasive = true
+		suite.AddChangeManager(&ChangeManager{})
+		if got := suite.workers(); got != 1 {
+			t.Errorf("workers() = %d, want 1 with a change manager attached", got)
+		}
+	})
+
+	t.Run("Evidence Payload Forces Serial", func(t *testing.T) {
+		suite := &EvaluationSuite{config: setBasicConfig(), payload: &gemara.EvidenceCollector{}}
+		suite.config.EvaluationWorkers = 4
+		if got := suite.workers(); got != 1 {
+			t.Errorf("workers() = %d, want 1 for an evidence-collecting payload", got)
+		}
+	})
+}
+
+func TestEvaluationSuite_PolicySelection(t *testing.T) {
+	cfg := setBasicConfig()
+	cfg.Policy.Document = &gemara.Policy{
+		Metadata: gemara.Metadata{Id: "storage-policy", Type: gemara.PolicyArtifact},
+		Imports: gemara.Imports{Catalogs: []gemara.CatalogImport{{
+			ReferenceId: "CCC.Parallel",
+			Exclusions:  []string{"CCC.Core.C00"},
+			AssessmentRequirementModifications: []gemara.AssessmentRequirementModifier{
+				{TargetId: "CCC.Core.C01.TR01", ModificationType: gemara.ModRemove, ModificationRationale: "covered by the platform"},
+				{TargetId: "CCC.Core.C02.TR01", ModificationType: gemara.ModModify, Applicability: []string{"tlp-red"}},
+			},
+		}}},
+	}
+	catalog := getTestCatalogWithControls(4)
+	steps := make(map[string][]gemara.AssessmentStep)
+	for _, control := range catalog.Controls {
+		steps[control.AssessmentRequirements[0].Id] = []gemara.AssessmentStep{step_Fail}
+	}
+	steps["CCC.Core.C03.TR01"] = []gemara.AssessmentStep{step_Pass}
+	suite := &EvaluationSuite{CatalogId: catalog.Metadata.Id, catalog: catalog, config: cfg, steps: steps}
+	if err := suite.Evaluate("policy"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	want := []struct {
+		result  gemara.Result
+		message string
+	}{
+		{gemara.NotRun, "excluded by policy storage-policy"},
+		{gemara.NotRun, "removed by policy storage-policy: covered by the platform"},
+		{gemara.NotRun, ""}, // the policy narrows applicability to tlp-red
+		{gemara.Passed, "This step always passes"},
+	}
+	for i, evaluation := range suite.EvaluationLog.Evaluations {
+		assessment := evaluation.AssessmentLogs[0]
+		if assessment.Result != want[i].result || assessment.Message != want[i].message {
+			t.Errorf("%s: got %s %q, want %s %q", assessment.Requirement.EntryId,
+				assessment.Result, assessment.Message, want[i].result, want[i].message)
+		}
+	}
+	if suite.Result != gemara.Passed {
+		t.Errorf("requirements outside the policy should not fail the suite, got %s", suite.Result)
+	}
+}
+
+func TestEvaluationSuite_FilterSelection(t *testing.T) {
+	cfg := setBasicConfig()
+	cfg.Filter = config.Filter{Include: []string{"CCC.Core.C0*"}, Exclude: []string{"CCC.Core.C01.TR01"}}
+	catalog := getTestCatalogWithControls(3)
+	catalog.Controls = append(catalog.Controls, gemara.Control{
+		Id:                     "CCC.Other.C01",
+		Objective:              "Test objective",
+		AssessmentRequirements: []gemara.AssessmentRequirement{{Id: "CCC.Other.C01.TR01", Applicability: requestedApplicability}},
+	})
+	steps := map[string][]gemara.AssessmentStep{
+		"CCC.Core.C00.TR01":  {step_Pass},
+		"CCC.Core.C01.TR01":  {step_Fail},
+		"CCC.Core.C02.TR01":  {step_Pass},
+		"CCC.Other.C01.TR01": {step_Fail},
+	}
+	suite := &EvaluationSuite{CatalogId: catalog.Metadata.Id, catalog: catalog, config: cfg, steps: steps}
+	if err := suite.Evaluate("filter"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	want := map[string]string{
+		"CCC.Core.C00.TR01":  "This step always passes",
+		"CCC.Core.C01.TR01":  `excluded by filter "CCC.Core.C01.TR01"`,
+		"CCC.Core.C02.TR01":  "This step always passes",
+		"CCC.Other.C01.TR01": "not matched by the include filter",
+	}
+	for _, evaluation := range suite.EvaluationLog.Evaluations {
+		assessment := evaluation.AssessmentLogs[0]
+		if assessment.Message != want[assessment.Requirement.EntryId] {
+			t.Errorf("%s: got %s %q", assessment.Requirement.EntryId, assessment.Result, assessment.Message)
+		}
+	}
+	if suite.Result != gemara.Passed {
+		t.Errorf("filtered-out requirements should not fail the suite, got %s", suite.Result)
+	}
+}
@@ -0,0 +1,105 @@
+package pluginkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// AttachEvidence records evidence, such as an API response excerpt, a file
+// path, a hash or a URL, on the assessment of the step running with ctx. It
+// ends up in the assessment's Evidence in the EvaluationLog and in every
+// output format.
+//
+// Only steps registered with AddEvaluationSuiteContext are handed a context
+// that carries evidence; use T = any for untyped payloads. Steps registered
+// as a gemara.AssessmentStep or TypedAssessmentStep have no context, and
+// record evidence through a payload implementing gemara.HasEvidence instead.
+// Evidence without a CollectedAt is stamped with the time it was attached,
+// and evidence without an Id is given one from the requirement id once the
+// step returns. Evidence attached by a step that overran its deadline is
+// discarded with the step.
+//
+// AttachEvidence reports whether the evidence was recorded. It records
+// nothing with any other context, so a step can be unit tested without a
+// running suite, or once the step has been abandoned.
+func AttachEvidence(ctx context.Context, evidence ...gemara.Evidence) bool {
+	attached, ok := ctx.Value(stepEvidenceKey{}).(*stepEvidence)
+	return ok && attached.add(evidence)
+}
+
+type stepEvidenceKey struct{}
+
+// stepEvidence collects the evidence attached during one run of a step. It
+// is closed once the step returns or is abandoned, after which attaching does
+// nothing, so an abandoned step cannot reach the assessment.
+type stepEvidence struct {
+	mu       sync.Mutex
+	closed   bool
+	evidence []gemara.Evidence
+}
+
+func withStepEvidence(ctx context.Context) (context.Context, *stepEvidence) {
+	attached := &stepEvidence{}
+	return context.WithValue(ctx, stepEvidenceKey{}, attached), attached
+}
+
+// add records evidence, reporting false once the step's evidence is closed.
+func (s *stepEvidence) add(evidence []gemara.Evidence) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	now := gemara.Datetime(time.Now().Format(time.RFC3339))
+	for _, item := range evidence {
+		if item.CollectedAt == "" {
+			item.CollectedAt = now
+		}
+		s.evidence = append(s.evidence, item)
+	}
+	return true
+}
+
+// close stops further attaching and returns what was attached.
+func (s *stepEvidence) close() []gemara.Evidence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return s.evidence
+}
+
+// appendEvidence adds attached to the evidence of requirementId, numbering
+// any evidence the step left without an id.
+func appendEvidence(requirementId string, evidence *[]gemara.Evidence, attached []gemara.Evidence) {
+	for _, item := range attached {
+		if item.Id == "" {
+			item.Id = fmt.Sprintf("%s-evidence-%d", requirementId, len(*evidence)+1)
+		}
+		*evidence = append(*evidence, item)
+	}
+}
+
+// evidenceText renders evidence on one line, with its payload as compact
+// JSON, for the formats that have no structured place for it.
+func evidenceText(item gemara.Evidence) string {
+	text := item.Id
+	if item.Type != "" {
+		text += fmt.Sprintf(" (%s)", item.Type)
+	}
+	if item.Description != "" {
+		text += ": " + item.Description
+	}
+	if item.Payload != nil {
+		payload, err := json.Marshal(item.Payload)
+		if err != nil {
+			payload = []byte("payload could not be rendered: " + err.Error())
+		}
+		text += " " + string(payload)
+	}
+	return text
+}
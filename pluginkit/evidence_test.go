@@ -0,0 +1,137 @@
+package pluginkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// evidenceSuite registers step for CCC.Core.C01.TR01 through the context
+// helpers and evaluates it.
+func evidenceSuite(t *testing.T, stepTimeout time.Duration, step ContextAssessmentStep[testPayload]) *EvaluationSuite {
+	t.Helper()
+	cfg := setBasicConfig()
+	cfg.StepTimeout = stepTimeout
+	catalog := getTestCatalogWithRequirements()
+	v := &EvaluationOrchestrator{
+		config:            cfg,
+		referenceCatalogs: map[string]*gemara.ControlCatalog{catalog.Metadata.Id: catalog},
+	}
+	if err := AddEvaluationSuiteContext(v, catalog.Metadata.Id, nil, map[string][]ContextAssessmentStep[testPayload]{
+		"CCC.Core.C01.TR01": {step},
+	}); err != nil {
+		t.Fatalf("registering suite: %v", err)
+	}
+	suite := v.possibleSuites[0]
+	suite.payload = testPayload{}
+	if err := suite.Evaluate("evidence"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	return suite
+}
+
+func contextStep_AttachesEvidence(ctx context.Context, _ testPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+	AttachEvidence(ctx,
+		gemara.Evidence{Id: "branch-protection", Type: "api-response", Description: "GET /branches/main/protection", Payload: map[string]any{"enabled": true}},
+		gemara.Evidence{Type: "url", Payload: "https://example.com/org/repo/settings"},
+	)
+	return gemara.Passed, "branch protection is enabled", gemara.High
+}
+
+func TestAttachEvidence(t *testing.T) {
+	tests := []struct {
+		name        string
+		stepTimeout time.Duration
+		step        ContextAssessmentStep[testPayload]
+		wantIds     []string
+	}{
+		{
+			name:    "attached to the assessment",
+			step:    contextStep_AttachesEvidence,
+			wantIds: []string{"branch-protection", "CCC.Core.C01.TR01-evidence-2"},
+		},
+		{
+			name:        "dropped when the step overruns",
+			stepTimeout: 20 * time.Millisecond,
+			step: func(ctx context.Context, _ testPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+				AttachEvidence(ctx, gemara.Evidence{Type: "hash", Payload: "sha256:abc"})
+				<-ctx.Done()
+				AttachEvidence(ctx, gemara.Evidence{Type: "hash", Payload: "sha256:def"})
+				return gemara.Passed, "finished too late", gemara.High
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := evidenceSuite(t, tt.stepTimeout, tt.step)
+			evidence := suite.EvaluationLog.Evaluations[0].AssessmentLogs[0].Evidence
+
+			if len(evidence) != len(tt.wantIds) {
+				t.Fatalf("got %d pieces of evidence, want %d: %+v", len(evidence), len(tt.wantIds), evidence)
+			}
+			for i, id := range tt.wantIds {
+				if evidence[i].Id != id {
+					t.Errorf("evidence %d has id %q, want %q", i, evidence[i].Id, id)
+				}
+				if evidence[i].CollectedAt == "" {
+					t.Errorf("evidence %d was not stamped with its collection time", i)
+				}
+			}
+		})
+	}
+}
+
+func TestAttachEvidence_OutsideASuite(t *testing.T) {
+	// a step unit tested on its own must not panic or need a suite
+	result, _, _ := contextStep_AttachesEvidence(context.Background(), testPayload{})
+	if result != gemara.Passed {
+		t.Errorf("expected the step to run as usual, got %s", result)
+	}
+	if AttachEvidence(context.Background(), gemara.Evidence{Type: "url"}) {
+		t.Error("expected AttachEvidence to report that nothing was recorded outside a suite")
+	}
+}
+
+func TestAttachEvidence_ReportsWhetherRecorded(t *testing.T) {
+	var during bool
+	var ctxAfter context.Context
+	evidenceSuite(t, 0, func(ctx context.Context, _ testPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+		during = AttachEvidence(ctx, gemara.Evidence{Type: "url"})
+		ctxAfter = ctx
+		return gemara.Passed, "passed", gemara.High
+	})
+	if !during {
+		t.Error("expected AttachEvidence to report recording evidence while the step runs")
+	}
+	if AttachEvidence(ctxAfter, gemara.Evidence{Type: "url"}) {
+		t.Error("expected AttachEvidence to report that nothing was recorded once the step returned")
+	}
+}
+
+func TestAttachEvidence_ReachesEveryOutput(t *testing.T) {
+	suite := evidenceSuite(t, 0, contextStep_AttachesEvidence)
+	v := &EvaluationOrchestrator{
+		ServiceName:       "evidence",
+		PluginName:        "test-plugin",
+		config:            suite.config,
+		Evaluation_Suites: []*EvaluationSuite{suite},
+	}
+	v.stampEvaluationLog(suite)
+
+	for _, output := range []string{"json", "yaml", "gemara", "html", "junit", "sarif", "oscal"} {
+		t.Run(output, func(t *testing.T) {
+			result, err := v.marshalResults(output)
+			if err != nil {
+				t.Fatalf("marshalling %s failed: %v", output, err)
+			}
+			for _, want := range []string{"GET /branches/main/protection", "example.com/org/repo/settings"} {
+				if !strings.Contains(string(result), want) {
+					t.Errorf("%s output does not carry the evidence %q", output, want)
+				}
+			}
+		})
+	}
+}
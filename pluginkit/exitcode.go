@@ -18,6 +18,8 @@ import (
 // A non-nil error wrapping ErrRuntime → InternalError; ErrDevBug → BadUsage;
 // any other non-nil error → InternalError. With nil error, suite results
 // containing Failed/NeedsReview/Unknown → TestFail; otherwise TestPass.
+// Assessments covered by an active waiver are left out of that check, so an
+// accepted risk does not fail the run until its waiver expires.
 func ExitCodeFor(orch *EvaluationOrchestrator, mobilizeErr error) int {
 	if mobilizeErr != nil {
 		if errors.Is(mobilizeErr, ErrDevBug) {
@@ -29,7 +31,7 @@ func ExitCodeFor(orch *EvaluationOrchestrator, mobilizeErr error) int {
 		return shared.TestPass
 	}
 	for _, suite := range orch.Evaluation_Suites {
-		switch suite.Result {
+		switch suite.unwaivedResult() {
 		case gemara.Failed, gemara.NeedsReview, gemara.Unknown:
 			return shared.TestFail
 		}
@@ -0,0 +1,202 @@
+package pluginkit
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+
+	"github.com/privateerproj/privateer-sdk/ai/assist"
+)
+
+//go:embed html_report.tmpl
+var htmlReportTemplate string
+
+var htmlReport = template.Must(template.New("report").Parse(htmlReportTemplate))
+
+// htmlResults are the statuses the report can filter by, in display order.
+var htmlResults = []gemara.Result{gemara.Passed, gemara.Failed, gemara.NeedsReview, gemara.Unknown, gemara.NotApplicable, gemara.NotRun}
+
+// htmlReportView is the data behind the html output: one page per service,
+// laid out as catalog > control > requirement.
+type htmlReportView struct {
+	Service       string
+	PluginName    string
+	PluginVersion string
+	Generated     string
+	Statuses      []htmlStatus
+	Suites        []htmlSuite
+}
+
+type htmlStatus struct {
+	Label string
+	Class string
+	Count int
+}
+
+type htmlSuite struct {
+	Name           string
+	CatalogId      string
+	CatalogTitle   string
+	Result         string
+	ResultClass    string
+	StartTime      string
+	EndTime        string
+	CorruptedState bool
+	Controls       []htmlControl
+}
+
+type htmlControl struct {
+	Id          string
+	Title       string
+	Group       string
+	Result      string
+	ResultClass string
+	Message     string
+	Assessments []htmlAssessment
+}
+
+type htmlAssessment struct {
+	RequirementId   string
+	RequirementText string
+	Result          string
+	ResultClass     string
+	Message         string
+	Recommendation  string
+	Confidence      string
+	Steps           []string
+	StepsExecuted   int64
+	Evidence        []htmlEvidence
+}
+
+type htmlEvidence struct {
+	Id          string
+	Type        string
+	Description string
+	CollectedAt string
+	AI          bool
+	Payload     string
+}
+
+// marshalHTML renders the run as a single self-contained HTML page: styles and
+// the result filter script are inline, so the file can be opened or attached
+// as-is with no network access.
+func (v *EvaluationOrchestrator) marshalHTML() ([]byte, error) {
+	view := htmlReportView{
+		Service:       v.ServiceName,
+		PluginName:    v.PluginName,
+		PluginVersion: v.PluginVersion,
+		Generated:     time.Now().UTC().Format(time.RFC3339),
+	}
+	counts := make(map[gemara.Result]int)
+	for _, suite := range v.Evaluation_Suites {
+		view.Suites = append(view.Suites, suite.htmlView(counts))
+	}
+	for _, result := range htmlResults {
+		view.Statuses = append(view.Statuses, htmlStatus{Label: result.String(), Class: resultClass(result), Count: counts[result]})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlReport.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// htmlView flattens the suite's log for the template, tallying each assessment
+// result into counts for the filter bar.
+func (e *EvaluationSuite) htmlView(counts map[gemara.Result]int) htmlSuite {
+	suite := htmlSuite{
+		Name:           e.Name,
+		CatalogId:      e.CatalogId,
+		Result:         e.Result.String(),
+		ResultClass:    resultClass(e.Result),
+		StartTime:      e.StartTime,
+		EndTime:        e.EndTime,
+		CorruptedState: e.CorruptedState,
+	}
+
+	requirementText := make(map[string]string)
+	controlGroup := make(map[string]string)
+	if e.catalog != nil {
+		suite.CatalogTitle = e.catalog.Title
+		groupTitles := make(map[string]string, len(e.catalog.Groups))
+		for _, group := range e.catalog.Groups {
+			groupTitles[group.Id] = group.Title
+		}
+		for _, control := range e.catalog.Controls {
+			controlGroup[control.Id] = control.Group
+			if title := groupTitles[control.Group]; title != "" {
+				controlGroup[control.Id] = title
+			}
+			for _, requirement := range control.AssessmentRequirements {
+				requirementText[requirement.Id] = requirement.Text
+			}
+		}
+	}
+
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		control := htmlControl{
+			Id:          evaluation.Control.EntryId,
+			Title:       evaluation.Name,
+			Group:       controlGroup[evaluation.Control.EntryId],
+			Result:      evaluation.Result.String(),
+			ResultClass: resultClass(evaluation.Result),
+			Message:     evaluation.Message,
+		}
+		for _, log := range evaluation.AssessmentLogs {
+			counts[log.Result]++
+			assessment := htmlAssessment{
+				RequirementId:   log.Requirement.EntryId,
+				RequirementText: requirementText[log.Requirement.EntryId],
+				Result:          log.Result.String(),
+				ResultClass:     resultClass(log.Result),
+				Message:         log.Message,
+				Recommendation:  log.Recommendation,
+				StepsExecuted:   log.StepsExecuted,
+			}
+			if log.Result != gemara.NotRun {
+				assessment.Confidence = log.ConfidenceLevel.String()
+			}
+			for i, step := range log.Steps {
+				assessment.Steps = append(assessment.Steps, e.stepName(log.Requirement.EntryId, i, step))
+			}
+			for _, evidence := range log.Evidence {
+				assessment.Evidence = append(assessment.Evidence, htmlEvidenceView(evidence))
+			}
+			control.Assessments = append(control.Assessments, assessment)
+		}
+		suite.Controls = append(suite.Controls, control)
+	}
+	return suite
+}
+
+// htmlEvidenceView marks AI-assisted evidence so reviewers can tell it apart
+// from directly observed data, and pretty-prints the payload as JSON.
+func htmlEvidenceView(evidence gemara.Evidence) htmlEvidence {
+	view := htmlEvidence{
+		Id:          evidence.Id,
+		Type:        string(evidence.Type),
+		Description: evidence.Description,
+		CollectedAt: string(evidence.CollectedAt),
+		AI:          evidence.Type == assist.EvidenceType,
+	}
+	if evidence.Payload != nil {
+		payload, err := json.MarshalIndent(evidence.Payload, "", "  ")
+		if err != nil {
+			view.Payload = "payload could not be rendered: " + err.Error()
+		} else {
+			view.Payload = string(payload)
+		}
+	}
+	return view
+}
+
+// resultClass turns a result into the CSS class and filter key the template uses.
+func resultClass(result gemara.Result) string {
+	return strings.ToLower(strings.ReplaceAll(result.String(), " ", "-"))
+}
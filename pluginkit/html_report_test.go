@@ -0,0 +1,136 @@
+package pluginkit
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+
+	"github.com/privateerproj/privateer-sdk/ai/assist"
+)
+
+func TestEvaluationOrchestrator_WriteResults_HTML(t *testing.T) {
+	resultPath := writeResultsFixture(t, "html", false, nil)
+	if !strings.HasSuffix(resultPath, "test-service.html") {
+		t.Fatalf("expected an .html results file, got %s", resultPath)
+	}
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatalf("expected html output file at %s: %v", resultPath, err)
+	}
+	page := string(data)
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<h1>test-service</h1>",
+		"Plugin test-plugin 1.0.0",
+		`data-filter="passed"`,
+		`data-filter="needs-review"`,
+		`<tr class="assessment" data-result=`,
+		"assessment-good",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected %q in html output", want)
+		}
+	}
+	// Self-contained: nothing is fetched when the report is opened.
+	for _, external := range []string{"<link", " src=", "@import"} {
+		if strings.Contains(page, external) {
+			t.Errorf("html output should not reference external resources, found %q", external)
+		}
+	}
+}
+
+func TestEvaluationSuite_HTMLView(t *testing.T) {
+	cfg := setBasicConfig()
+	catalog := getTestCatalogWithRequirements()
+	catalog.Title = "Object Storage"
+	catalog.Groups = []gemara.Group{{Id: "CCC.Core", Title: "Core"}}
+	catalog.Controls[0].Group = "CCC.Core"
+	suite := &EvaluationSuite{
+		CatalogId: catalog.Metadata.Id,
+		catalog:   catalog,
+		config:    cfg,
+		steps: map[string][]gemara.AssessmentStep{
+			"CCC.Core.C01.TR01": {step_Pass, step_Fail},
+		},
+	}
+	if err := suite.Evaluate("html"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	counts := make(map[gemara.Result]int)
+	view := suite.htmlView(counts)
+
+	if view.CatalogTitle != "Object Storage" || len(view.Controls) != 1 {
+		t.Fatalf("unexpected suite view: %+v", view)
+	}
+	control := view.Controls[0]
+	if control.Id != "CCC.Core.C01" || control.Group != "Core" || control.ResultClass != "failed" {
+		t.Errorf("unexpected control view: %+v", control)
+	}
+	assessment := control.Assessments[0]
+	if !strings.HasPrefix(assessment.RequirementText, "When a port is exposed") {
+		t.Errorf("expected the requirement text from the catalog, got %q", assessment.RequirementText)
+	}
+	if len(assessment.Steps) != 2 || !strings.HasSuffix(assessment.Steps[1], "step_Fail") {
+		t.Errorf("expected both step names, got %v", assessment.Steps)
+	}
+	if assessment.Confidence != "Low" || assessment.StepsExecuted != 2 {
+		t.Errorf("expected the failing step's confidence and two executed steps, got %q and %d", assessment.Confidence, assessment.StepsExecuted)
+	}
+	if counts[gemara.Failed] != 1 {
+		t.Errorf("expected one failed assessment counted, got %v", counts)
+	}
+}
+
+func TestHTMLEvidenceView(t *testing.T) {
+	tests := []struct {
+		name        string
+		evidence    gemara.Evidence
+		wantAI      bool
+		wantPayload string
+	}{
+		{
+			name:        "ai evidence is marked",
+			evidence:    gemara.Evidence{Id: "req-1", Type: assist.EvidenceType, Description: "AI Assisted Review", Payload: map[string]string{"model": "m"}},
+			wantAI:      true,
+			wantPayload: "\"model\": \"m\"",
+		},
+		{
+			name:     "observed evidence without payload",
+			evidence: gemara.Evidence{Id: "obs-1", Type: "api-response"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := htmlEvidenceView(tt.evidence)
+			if view.AI != tt.wantAI {
+				t.Errorf("expected AI=%v, got %v", tt.wantAI, view.AI)
+			}
+			if !strings.Contains(view.Payload, tt.wantPayload) {
+				t.Errorf("expected payload to contain %q, got %q", tt.wantPayload, view.Payload)
+			}
+		})
+	}
+}
+
+func TestMarshalHTML_EscapesResultText(t *testing.T) {
+	cfg := setBasicConfig()
+	evalLog := createTestEvalLog()
+	evalLog.Evaluations[0].AssessmentLogs[0].Message = "<script>alert(1)</script>"
+	v := &EvaluationOrchestrator{
+		ServiceName:       "test-service",
+		config:            cfg,
+		Evaluation_Suites: []*EvaluationSuite{{CatalogId: "test-catalog", EvaluationLog: evalLog, config: cfg}},
+	}
+
+	data, err := v.marshalHTML()
+	if err != nil {
+		t.Fatalf("marshalHTML failed: %v", err)
+	}
+	if strings.Contains(string(data), "<script>alert(1)</script>") {
+		t.Error("expected step messages to be HTML-escaped")
+	}
+}
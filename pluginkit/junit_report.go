@@ -0,0 +1,168 @@
+package pluginkit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// junitTestSuites is the root of the junit output. The layout follows the
+// de facto JUnit XML schema that CI test dashboards ingest.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// marshalJUnit maps each suite to a testsuite and each assessment to a
+// testcase. Failed is a failure and NotRun/NotApplicable are skipped;
+// NeedsReview and Unknown are errors or skips as set by junit-unresolved.
+func (v *EvaluationOrchestrator) marshalJUnit() ([]byte, error) {
+	report := junitTestSuites{Name: v.ServiceName}
+	var total time.Duration
+	for _, suite := range v.Evaluation_Suites {
+		testSuite, elapsed := suite.junitTestSuite(v.config.JUnitUnresolved)
+		testSuite.Properties = append([]junitProperty{
+			{Name: "plugin-name", Value: v.PluginName},
+			{Name: "plugin-version", Value: v.PluginVersion},
+		}, testSuite.Properties...)
+		report.Tests += testSuite.Tests
+		report.Failures += testSuite.Failures
+		report.Errors += testSuite.Errors
+		report.Skipped += testSuite.Skipped
+		total += elapsed
+		report.Suites = append(report.Suites, testSuite)
+	}
+	report.Time = junitSeconds(total)
+
+	out, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// junitTestSuite builds the suite's testsuite and returns its elapsed time,
+// taken from the suite's start and end. Testcase times are the sum of the
+// requirement's recorded step durations.
+func (e *EvaluationSuite) junitTestSuite(unresolved string) (junitTestSuite, time.Duration) {
+	var elapsed time.Duration
+	start, startErr := time.Parse(time.RFC3339Nano, e.StartTime)
+	end, endErr := time.Parse(time.RFC3339Nano, e.EndTime)
+	if startErr == nil && endErr == nil {
+		elapsed = end.Sub(start)
+	}
+	testSuite := junitTestSuite{
+		Name:      e.Name,
+		Time:      junitSeconds(elapsed),
+		Timestamp: e.StartTime,
+		Properties: []junitProperty{
+			{Name: "catalog-id", Value: e.CatalogId},
+			{Name: "corrupted-state", Value: fmt.Sprint(e.CorruptedState)},
+		},
+	}
+
+	stepDurations := make(map[string]time.Duration)
+	for _, timing := range e.stepTimings {
+		stepDurations[timing.ControlId+"/"+timing.RequirementId] += time.Duration(timing.DurationNs)
+	}
+
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		for _, log := range evaluation.AssessmentLogs {
+			testCase := junitTestCase{
+				Name:      log.Requirement.EntryId,
+				Classname: evaluation.Control.EntryId,
+				Time:      junitSeconds(stepDurations[evaluation.Control.EntryId+"/"+log.Requirement.EntryId]),
+				SystemOut: e.junitSystemOut(log),
+			}
+			outcome := &junitMessage{Message: log.Message, Type: log.Result.String()}
+			switch log.Result {
+			case gemara.Passed:
+			case gemara.Failed:
+				testCase.Failure = outcome
+				testSuite.Failures++
+			case gemara.NeedsReview, gemara.Unknown:
+				if unresolved == "skipped" {
+					testCase.Skipped = outcome
+					testSuite.Skipped++
+				} else {
+					testCase.Error = outcome
+					testSuite.Errors++
+				}
+			default:
+				testCase.Skipped = outcome
+				testSuite.Skipped++
+			}
+			testSuite.Tests++
+			testSuite.TestCases = append(testSuite.TestCases, testCase)
+		}
+	}
+	return testSuite, elapsed
+}
+
+// junitSystemOut carries the assessment details a dashboard has no field for.
+func (e *EvaluationSuite) junitSystemOut(log *gemara.AssessmentLog) string {
+	var lines []string
+	if log.Result != gemara.NotRun {
+		lines = append(lines, "confidence: "+log.ConfidenceLevel.String())
+	}
+	if len(log.Steps) > 0 {
+		names := make([]string, len(log.Steps))
+		for i, step := range log.Steps {
+			names[i] = e.stepName(log.Requirement.EntryId, i, step)
+		}
+		lines = append(lines, fmt.Sprintf("steps: %s (%d executed)", strings.Join(names, ", "), log.StepsExecuted))
+	}
+	if log.Recommendation != "" {
+		lines = append(lines, "recommendation: "+log.Recommendation)
+	}
+	for _, item := range log.Evidence {
+		lines = append(lines, "evidence: "+evidenceText(item))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// junitSeconds formats a duration the way JUnit consumers expect: decimal seconds.
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,121 @@
+package pluginkit
+
+import (
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// junitFixture evaluates a four-control suite (slow pass, fail, needs review,
+// and Unknown for a requirement without steps) with junit output selected, so
+// step timings are recorded.
+func junitFixture(t *testing.T, unresolved string) *EvaluationOrchestrator {
+	t.Helper()
+	cfg := setBasicConfig()
+	cfg.Output = []string{"junit"}
+	cfg.JUnitUnresolved = unresolved
+	catalog := getTestCatalogWithControls(4)
+	suite := &EvaluationSuite{
+		CatalogId: catalog.Metadata.Id,
+		catalog:   catalog,
+		config:    cfg,
+		steps: map[string][]gemara.AssessmentStep{
+			"CCC.Core.C00.TR01": {step_Slow},
+			"CCC.Core.C01.TR01": {step_Pass, step_Fail},
+			"CCC.Core.C02.TR01": {step_NeedsReview},
+		},
+	}
+	if err := suite.Evaluate("junit"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	return &EvaluationOrchestrator{
+		ServiceName:       "junit",
+		PluginName:        "test-plugin",
+		PluginVersion:     "1.0.0",
+		config:            cfg,
+		Evaluation_Suites: []*EvaluationSuite{suite},
+	}
+}
+
+func TestMarshalJUnit(t *testing.T) {
+	tests := []struct {
+		name                    string
+		unresolved              string
+		wantErrors, wantSkipped int
+		wantUnresolvedSkipped   bool
+	}{
+		{name: "unresolved as error", unresolved: "error", wantErrors: 2},
+		{name: "unresolved as skipped", unresolved: "skipped", wantSkipped: 2, wantUnresolvedSkipped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := junitFixture(t, tt.unresolved).marshalJUnit()
+			if err != nil {
+				t.Fatalf("marshalJUnit failed: %v", err)
+			}
+			if !strings.HasPrefix(string(data), xml.Header) {
+				t.Error("expected an XML declaration")
+			}
+			var report junitTestSuites
+			if err := xml.Unmarshal(data, &report); err != nil {
+				t.Fatalf("junit output is not valid XML: %v\n%s", err, data)
+			}
+
+			if report.Tests != 4 || report.Failures != 1 || report.Errors != tt.wantErrors || report.Skipped != tt.wantSkipped {
+				t.Errorf("unexpected totals: tests=%d failures=%d errors=%d skipped=%d",
+					report.Tests, report.Failures, report.Errors, report.Skipped)
+			}
+			if len(report.Suites) != 1 || len(report.Suites[0].TestCases) != 4 {
+				t.Fatalf("expected one testsuite with four testcases, got %+v", report.Suites)
+			}
+			suite := report.Suites[0]
+			if suite.Name != "junit_CCC.Parallel" || suite.Timestamp == "" || report.Time != suite.Time {
+				t.Errorf("unexpected testsuite header: %+v", suite)
+			}
+			if suite.Properties[0].Name != "plugin-name" || suite.Properties[0].Value != "test-plugin" {
+				t.Errorf("expected plugin properties first, got %v", suite.Properties)
+			}
+
+			slow, failed, review, unknown := suite.TestCases[0], suite.TestCases[1], suite.TestCases[2], suite.TestCases[3]
+			if slow.Classname != "CCC.Core.C00" || slow.Name != "CCC.Core.C00.TR01" || slow.Time == "0.000" {
+				t.Errorf("expected the slow step's duration on its testcase, got %+v", slow)
+			}
+			if failed.Failure == nil || failed.Failure.Message != "This step always fails" {
+				t.Errorf("expected a failure, got %+v", failed)
+			}
+			if !strings.Contains(failed.SystemOut, "step_Fail (2 executed)") {
+				t.Errorf("expected step names in system-out, got %q", failed.SystemOut)
+			}
+			for _, unresolved := range []junitTestCase{review, unknown} {
+				if tt.wantUnresolvedSkipped != (unresolved.Skipped != nil) || tt.wantUnresolvedSkipped == (unresolved.Error != nil) {
+					t.Errorf("%s mapped incorrectly: %+v", unresolved.Name, unresolved)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluationOrchestrator_WriteResults_JUnit(t *testing.T) {
+	resultPath := writeResultsFixture(t, "junit", false, nil)
+	if !strings.HasSuffix(resultPath, "test-service.xml") {
+		t.Fatalf("expected an .xml results file, got %s", resultPath)
+	}
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatalf("expected junit output file at %s: %v", resultPath, err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("junit output is not valid XML: %v", err)
+	}
+	// the fixture's log was never evaluated, so its one assessment is Not Run
+	if report.Name != "test-service" || report.Tests != 1 || report.Skipped != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if skipped := report.Suites[0].TestCases[0].Skipped; skipped == nil || skipped.Type != gemara.NotRun.String() {
+		t.Errorf("expected a Not Run assessment to be skipped, got %+v", report.Suites[0].TestCases[0])
+	}
+}
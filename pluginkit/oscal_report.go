@@ -0,0 +1,205 @@
+package pluginkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/validation"
+	oscal "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/gemaraproj/go-gemara"
+	"github.com/gemaraproj/go-gemara/gemaraconv"
+)
+
+// maxOSCALValidationErrors bounds how many schema violations are quoted in the
+// error, so one systematic problem does not bury the log.
+const maxOSCALValidationErrors = 5
+
+// marshalOSCAL converts the run into one OSCAL assessment-results document:
+// each suite's EvaluationLog becomes a result with an observation per
+// assessment and a finding per control, and the TargetBuilder resource becomes
+// the subject of every observation. The document is validated against the
+// OSCAL schema before it is returned, so an invalid document is never written.
+func (v *EvaluationOrchestrator) marshalOSCAL() ([]byte, error) {
+	var document *oscal.AssessmentResults
+	for _, suite := range v.Evaluation_Suites {
+		converted, err := gemaraconv.EvaluationLog(suite.EvaluationLog).ToOSCALAssessmentResults(gemaraconv.WithCatalog(suite.catalog))
+		if err != nil {
+			return nil, fmt.Errorf("converting %s to OSCAL: %w", suite.CatalogId, err)
+		}
+		for i := range converted.Results {
+			normalizeOSCALResult(&converted.Results[i])
+			referenceSubjects(&converted.Results[i])
+			attachOSCALEvidence(&converted.Results[i], suite.EvaluationLog)
+		}
+		if document == nil {
+			document = &converted
+			continue
+		}
+		document.Results = append(document.Results, converted.Results...)
+	}
+	if document == nil {
+		return nil, fmt.Errorf("no evaluation suites were run, and OSCAL assessment-results requires at least one result")
+	}
+	document.Metadata.Title = fmt.Sprintf("Assessment Results: %s", v.ServiceName)
+	if v.PluginVersion != "" {
+		document.Metadata.Version = v.PluginVersion
+	}
+
+	model := oscal.OscalModels{AssessmentResults: document}
+	if err := validateOSCAL(model); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(model, "", "  ")
+}
+
+// normalizeOSCALResult repairs the two places where a converted result can
+// fall outside the OSCAL schema: a finding's status reason must be pass, fail
+// or other, so results such as Needs Review are reported as "other" with the
+// gemara result kept in the remarks; and empty property values are dropped
+// from the target inventory item.
+func normalizeOSCALResult(result *oscal.Result) {
+	if result.Findings != nil {
+		findings := *result.Findings
+		for i := range findings {
+			status := &findings[i].Target.Status
+			switch status.Reason {
+			case "", "pass", "fail", "other":
+			default:
+				status.Remarks = status.Reason
+				status.Reason = "other"
+			}
+		}
+	}
+	if result.LocalDefinitions == nil || result.LocalDefinitions.InventoryItems == nil {
+		return
+	}
+	items := *result.LocalDefinitions.InventoryItems
+	for i := range items {
+		if items[i].Props == nil {
+			continue
+		}
+		var props []oscal.Property
+		for _, prop := range *items[i].Props {
+			if strings.TrimSpace(prop.Value) != "" {
+				props = append(props, prop)
+			}
+		}
+		items[i].Props = nil
+		if len(props) > 0 {
+			items[i].Props = &props
+		}
+	}
+}
+
+// referenceSubjects points each observation at the result's target inventory
+// item, so the assessed resource is the subject of what was observed.
+func referenceSubjects(result *oscal.Result) {
+	if result.LocalDefinitions == nil || result.LocalDefinitions.InventoryItems == nil || result.Observations == nil {
+		return
+	}
+	items := *result.LocalDefinitions.InventoryItems
+	if len(items) == 0 {
+		return
+	}
+	subject := oscal.SubjectReference{SubjectUuid: items[0].UUID, Type: "inventory-item", Title: items[0].Description}
+	observations := *result.Observations
+	for i := range observations {
+		observations[i].Subjects = &[]oscal.SubjectReference{subject}
+	}
+}
+
+// attachOSCALEvidence lists each assessment's evidence as relevant evidence
+// of its observation, found through the observation's control-id and
+// requirement-id properties.
+func attachOSCALEvidence(result *oscal.Result, log gemara.EvaluationLog) {
+	if result.Observations == nil {
+		return
+	}
+	evidence := make(map[string][]gemara.Evidence)
+	for _, evaluation := range log.Evaluations {
+		for _, assessment := range evaluation.AssessmentLogs {
+			if assessment != nil && len(assessment.Evidence) > 0 {
+				evidence[evaluation.Control.EntryId+"/"+assessment.Requirement.EntryId] = assessment.Evidence
+			}
+		}
+	}
+	if len(evidence) == 0 {
+		return
+	}
+	observations := *result.Observations
+	for i := range observations {
+		if observations[i].Props == nil {
+			continue
+		}
+		var controlId, requirementId string
+		for _, prop := range *observations[i].Props {
+			switch prop.Name {
+			case "control-id":
+				controlId = prop.Value
+			case "requirement-id":
+				requirementId = prop.Value
+			}
+		}
+		var relevant []oscal.RelevantEvidence
+		for _, item := range evidence[controlId+"/"+requirementId] {
+			relevant = append(relevant, oscalEvidence(item))
+		}
+		if len(relevant) > 0 {
+			observations[i].RelevantEvidence = &relevant
+		}
+	}
+}
+
+// oscalEvidence describes evidence in OSCAL terms, keeping its id and type as
+// properties and its payload as JSON in the remarks.
+func oscalEvidence(item gemara.Evidence) oscal.RelevantEvidence {
+	relevant := oscal.RelevantEvidence{Description: item.Description}
+	if relevant.Description == "" {
+		relevant.Description = fmt.Sprintf("Evidence %s", item.Id)
+	}
+	var props []oscal.Property
+	if item.Id != "" {
+		props = append(props, oscal.Property{Name: "evidence-id", Value: item.Id})
+	}
+	if item.Type != "" {
+		props = append(props, oscal.Property{Name: "evidence-type", Value: string(item.Type)})
+	}
+	if item.CollectedAt != "" {
+		props = append(props, oscal.Property{Name: "collected-at", Value: string(item.CollectedAt)})
+	}
+	if len(props) > 0 {
+		relevant.Props = &props
+	}
+	if item.Payload != nil {
+		if payload, err := json.Marshal(item.Payload); err == nil {
+			relevant.Remarks = string(payload)
+		}
+	}
+	return relevant
+}
+
+// validateOSCAL checks the document against the OSCAL schema for its version.
+func validateOSCAL(model oscal.OscalModels) error {
+	validator, err := validation.NewValidator(model)
+	if err != nil {
+		return fmt.Errorf("preparing OSCAL validation: %w", err)
+	}
+	validateErr := validator.Validate()
+	if validateErr == nil {
+		return nil
+	}
+	result, _ := validator.GetValidationResult()
+	var problems []string
+	for i, violation := range result.Errors {
+		if i == maxOSCALValidationErrors {
+			problems = append(problems, fmt.Sprintf("and %d more", len(result.Errors)-i))
+			break
+		}
+		problems = append(problems, fmt.Sprintf("%s: %s", violation.InstanceLocation, violation.Error))
+	}
+	if len(problems) == 0 {
+		problems = append(problems, validateErr.Error())
+	}
+	return fmt.Errorf("OSCAL assessment-results failed schema validation: %s", strings.Join(problems, "; "))
+}
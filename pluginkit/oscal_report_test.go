@@ -0,0 +1,138 @@
+package pluginkit
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	oscal "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/gemaraproj/go-gemara"
+
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+func TestEvaluationOrchestrator_WriteResults_OSCAL(t *testing.T) {
+	resultPath := writeResultsFixture(t, "oscal", false, nil)
+	if !strings.HasSuffix(resultPath, "test-service.oscal.json") {
+		t.Fatalf("expected an .oscal.json results file, got %s", resultPath)
+	}
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatalf("expected oscal output file at %s: %v", resultPath, err)
+	}
+	var model oscal.OscalModels
+	if err := json.Unmarshal(data, &model); err != nil {
+		t.Fatalf("oscal output is not valid JSON: %v", err)
+	}
+	if model.AssessmentResults == nil || len(model.AssessmentResults.Results) != 1 {
+		t.Fatalf("expected one assessment-results result, got %+v", model.AssessmentResults)
+	}
+	if model.AssessmentResults.Metadata.Title != "Assessment Results: test-service" {
+		t.Errorf("unexpected title %q", model.AssessmentResults.Metadata.Title)
+	}
+}
+
+func TestMarshalOSCAL_EvaluatedSuites(t *testing.T) {
+	cfg := setBasicConfig()
+	var suites []*EvaluationSuite
+	for _, catalogId := range []string{"CCC.ObjStor", "CCC.Core"} {
+		catalog := getTestCatalogWithID(catalogId)
+		suite := &EvaluationSuite{
+			CatalogId: catalogId,
+			catalog:   catalog,
+			config:    cfg,
+			steps:     map[string][]gemara.AssessmentStep{"CCC.Core.C01.TR01": {step_Pass, step_Fail}},
+		}
+		if err := suite.Evaluate("oscal"); err != nil {
+			t.Fatalf("Evaluate failed: %v", err)
+		}
+		suites = append(suites, suite)
+	}
+	v := &EvaluationOrchestrator{
+		ServiceName:       "oscal",
+		PluginName:        "test-plugin",
+		PluginVersion:     "1.2.3",
+		config:            cfg,
+		Evaluation_Suites: suites,
+	}
+	v.AddTargetBuilder(func(_ *config.Config) gemara.Resource {
+		return gemara.Resource{Id: "bucket-1", Name: "my-bucket", Type: gemara.Software}
+	})
+	for _, suite := range suites {
+		v.stampEvaluationLog(suite)
+	}
+
+	data, err := v.marshalOSCAL()
+	if err != nil {
+		t.Fatalf("marshalOSCAL failed: %v", err)
+	}
+	var model oscal.OscalModels
+	if err := json.Unmarshal(data, &model); err != nil {
+		t.Fatalf("oscal output is not valid JSON: %v", err)
+	}
+	document := model.AssessmentResults
+	if len(document.Results) != 2 {
+		t.Fatalf("expected a result per suite, got %d", len(document.Results))
+	}
+	if document.Metadata.Version != "1.2.3" {
+		t.Errorf("expected the plugin version on the document, got %q", document.Metadata.Version)
+	}
+
+	result := document.Results[0]
+	findings := *result.Findings
+	if len(findings) != 1 || findings[0].Target.TargetId != "CCC.Core.C01" || findings[0].Target.Status.State != "not-satisfied" {
+		t.Errorf("expected one not-satisfied finding for the failed control, got %+v", findings)
+	}
+	item := (*result.LocalDefinitions.InventoryItems)[0]
+	if item.Description != "my-bucket" {
+		t.Errorf("expected the TargetBuilder resource as the inventory item, got %q", item.Description)
+	}
+	observations := *result.Observations
+	if len(observations) != 1 || observations[0].Subjects == nil || (*observations[0].Subjects)[0].SubjectUuid != item.UUID {
+		t.Errorf("expected each observation to reference the target as its subject, got %+v", observations)
+	}
+}
+
+func TestMarshalOSCAL_NoSuites(t *testing.T) {
+	v := &EvaluationOrchestrator{ServiceName: "oscal", config: setBasicConfig()}
+	if _, err := v.marshalOSCAL(); err == nil || !strings.Contains(err.Error(), "no evaluation suites") {
+		t.Errorf("expected an error for a run without suites, got %v", err)
+	}
+}
+
+func TestValidateOSCAL_RejectsInvalidDocument(t *testing.T) {
+	model := oscal.OscalModels{AssessmentResults: &oscal.AssessmentResults{
+		UUID:     "not-a-uuid",
+		Metadata: oscal.Metadata{Title: "broken", OscalVersion: oscal.Version, Version: "1"},
+	}}
+	err := validateOSCAL(model)
+	if err == nil || !strings.Contains(err.Error(), "failed schema validation") {
+		t.Errorf("expected a schema validation error, got %v", err)
+	}
+}
+
+func TestNormalizeOSCALResult(t *testing.T) {
+	findings := []oscal.Finding{
+		{Target: oscal.FindingTarget{Status: oscal.ObjectiveStatus{State: "satisfied"}}},
+		{Target: oscal.FindingTarget{Status: oscal.ObjectiveStatus{State: "not-satisfied", Reason: "Needs Review"}}},
+	}
+	props := []oscal.Property{{Name: "gemara-resource-id", Value: "bucket-1"}, {Name: "name", Value: ""}}
+	result := oscal.Result{
+		Findings:         &findings,
+		LocalDefinitions: &oscal.LocalDefinitions{InventoryItems: &[]oscal.InventoryItem{{Props: &props}}},
+	}
+
+	normalizeOSCALResult(&result)
+
+	if status := findings[0].Target.Status; status.Reason != "" || status.Remarks != "" {
+		t.Errorf("a passed finding should be unchanged, got %+v", status)
+	}
+	if status := findings[1].Target.Status; status.Reason != "other" || status.Remarks != "Needs Review" {
+		t.Errorf("expected the gemara result moved into remarks, got %+v", status)
+	}
+	kept := (*result.LocalDefinitions.InventoryItems)[0].Props
+	if kept == nil || len(*kept) != 1 || (*kept)[0].Name != "gemara-resource-id" {
+		t.Errorf("expected only non-empty props to be kept, got %v", kept)
+	}
+}
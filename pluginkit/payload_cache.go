@@ -0,0 +1,179 @@
+package pluginkit
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// payloadCache is the opt-in cache of loader payloads kept between runs. Each
+// entry is a payload fixture, stored under a key derived from the plugin, its
+// version, the service and a hash of the service's vars, so changing any of
+// them misses the cache. An entry expires payload-cache-ttl after it was first
+// written.
+type payloadCache struct {
+	path  string
+	entry *payloadFixture
+	dirty bool // entry gained payloads that were loaded live
+
+	warnedUnknownType bool // the unknown payload type warning is given once per run
+}
+
+// openPayloadCache returns the cache entry for this run, or nil when the cache
+// is off. The cache only ever speeds a run up: a missing, unreadable or
+// expired entry starts a fresh one rather than failing.
+func (v *EvaluationOrchestrator) openPayloadCache() *payloadCache {
+	if v.config.PayloadCacheTTL <= 0 {
+		return nil
+	}
+	key, err := payloadCacheKey(v.PluginName, v.PluginVersion, v.config.ServiceName, v.config.Vars)
+	if err != nil {
+		v.config.Logger.Warn("payload cache disabled for this run", "error", err)
+		return nil
+	}
+	cache := &payloadCache{path: filepath.Join(v.config.PayloadCacheDirectory, key+".json")}
+
+	entry, err := readPayloadFixture(cache.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		v.config.Logger.Warn("ignoring unreadable payload cache entry", "path", cache.path, "error", err)
+	case entry.expired(v.config.PayloadCacheTTL):
+		v.config.Logger.Debug("payload cache entry expired", "path", cache.path, "recorded-at", entry.RecordedAt)
+	default:
+		cache.entry = entry
+		return cache
+	}
+	cache.entry = v.newPayloadFixture()
+	return cache
+}
+
+// payloadCacheKey hashes everything a cached payload depends on. Vars are
+// hashed rather than stored, since they often hold credentials.
+func payloadCacheKey(pluginName, pluginVersion, serviceName string, vars map[string]any) (string, error) {
+	data, err := json.Marshal(struct {
+		Plugin  string         `json:"plugin"`
+		Version string         `json:"version"`
+		Service string         `json:"service"`
+		Vars    map[string]any `json:"vars"`
+	}{pluginName, pluginVersion, serviceName, vars})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (f *payloadFixture) expired(ttl time.Duration) bool {
+	recordedAt, err := time.Parse(time.RFC3339, f.RecordedAt)
+	return err != nil || time.Since(recordedAt) > ttl
+}
+
+// get decodes the cached payload for scope, reporting false when the cache is
+// off, holds none, or the payload no longer fits the payload type.
+func (c *payloadCache) get(v *EvaluationOrchestrator, scope string, payloadType reflect.Type) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	if _, ok := c.entry.Payloads[scope]; !ok {
+		return nil, false
+	}
+	if payloadType == nil || lossyJSONField(payloadType) != "" {
+		v.config.Logger.Debug("cannot use the cached payload: its type cannot be decoded faithfully", "scope", scope)
+		return nil, false
+	}
+	data, err := c.entry.replay(scope, payloadType)
+	if err != nil {
+		v.config.Logger.Debug("ignoring unusable cached payload", "scope", scope, "error", err)
+		return nil, false
+	}
+	return data, true
+}
+
+// put adds a live-loaded payload to the entry. A payload that could not be
+// read back as it was loaded is not cached, since it was fetched with the
+// service's credentials and would only sit on disk.
+func (c *payloadCache) put(v *EvaluationOrchestrator, scope string, payload any, payloadType reflect.Type) {
+	if payloadType == nil {
+		if !c.warnedUnknownType {
+			c.warnedUnknownType = true
+			v.config.Logger.Warn("payloads are not cached: the cache needs a known payload type; register typed steps or call SetPayloadType")
+		}
+		return
+	}
+	if field := lossyJSONField(payloadType); field != "" {
+		v.config.Logger.Warn("payload not cached: a cached copy would differ from a live load",
+			"scope", scope, "type", payloadType.String(), "field", field)
+		return
+	}
+	if err := c.entry.record(scope, payload); err != nil {
+		v.config.Logger.Warn("payload not cached", "scope", scope, "error", err)
+		return
+	}
+	c.dirty = true
+}
+
+// save writes the entry back when the run loaded anything live.
+func (c *payloadCache) save(v *EvaluationOrchestrator) {
+	if !c.dirty {
+		return
+	}
+	if err := c.entry.write(c.path); err != nil {
+		v.config.Logger.Warn("failed to save the payload cache", "path", c.path, "error", err)
+	}
+}
+
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// lossyJSONField names the first field of t that a JSON round trip loses:
+// unexported or "-" tagged fields come back zero, and interface fields come
+// back as maps and slices rather than the values the loader stored. It is
+// empty when t round-trips, including through its own (un)marshaling methods.
+func lossyJSONField(t reflect.Type) string {
+	return lossyField(t, t.String(), make(map[reflect.Type]bool))
+}
+
+func lossyField(t reflect.Type, at string, seen map[reflect.Type]bool) string {
+	if seen[t] {
+		return ""
+	}
+	seen[t] = true
+	pointer := reflect.PointerTo(t)
+	if (t.Implements(jsonMarshalerType) || pointer.Implements(jsonMarshalerType)) && pointer.Implements(jsonUnmarshalerType) ||
+		pointer.Implements(textUnmarshalerType) {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return at
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return lossyField(t.Elem(), at, seen)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if lossy := lossyField(field.Type, at+"."+field.Name, seen); lossy != "" {
+					return lossy
+				}
+				continue
+			}
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				return at + "." + field.Name
+			}
+			if lossy := lossyField(field.Type, at+"."+field.Name, seen); lossy != "" {
+				return lossy
+			}
+		}
+	}
+	return ""
+}
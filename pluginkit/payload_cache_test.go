@@ -0,0 +1,240 @@
+package pluginkit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+// cachedOrchestrator has one typed suite sharing the orchestrator payload, with
+// the payload cache on and benchmark timings collected.
+func cachedOrchestrator(cfg *config.Config, loader DataLoader) *EvaluationOrchestrator {
+	return &EvaluationOrchestrator{
+		PluginName:    "test-plugin",
+		PluginVersion: "1.2.3",
+		config:        cfg,
+		loader:        loader,
+		benchmark:     &BenchmarkReport{},
+		possibleSuites: []*EvaluationSuite{
+			{CatalogId: "CCC.ObjStor", catalog: getTestCatalogWithRequirements(), config: cfg, payloadType: reflect.TypeFor[testPayload]()},
+		},
+	}
+}
+
+func cacheConfig(t *testing.T, vars map[string]interface{}) *config.Config {
+	cfg := setBasicConfig()
+	cfg.PayloadCacheTTL = time.Hour
+	cfg.PayloadCacheDirectory = t.TempDir()
+	cfg.Vars = vars
+	return cfg
+}
+
+func TestLoadPayload_CacheHit(t *testing.T) {
+	cfg := cacheConfig(t, map[string]interface{}{"owner": "org"})
+	calls := 0
+	live := func(*config.Config) (any, error) {
+		calls++
+		return testPayload{Repo: "org/repo"}, nil
+	}
+
+	first := cachedOrchestrator(cfg, live)
+	if err := first.loadPayload(context.Background()); err != nil {
+		t.Fatalf("first load failed: %v", err)
+	}
+	if calls != 1 || first.benchmark.Loaders[0].Source != "" {
+		t.Fatalf("expected a live load on a cold cache, got %d calls and %+v", calls, first.benchmark.Loaders)
+	}
+
+	second := cachedOrchestrator(cfg, failingLoader)
+	if err := second.loadPayload(context.Background()); err != nil {
+		t.Fatalf("cached load failed: %v", err)
+	}
+	if got, ok := second.Payload.(testPayload); !ok || got.Repo != "org/repo" {
+		t.Errorf("cached payload = %#v, want testPayload{Repo: org/repo}", second.Payload)
+	}
+	if len(second.benchmark.Loaders) != 1 || second.benchmark.Loaders[0].Source != LoaderSourceCache {
+		t.Errorf("expected the benchmark to report a cache hit, got %+v", second.benchmark.Loaders)
+	}
+}
+
+func TestLoadPayload_CacheMiss(t *testing.T) {
+	tests := []struct {
+		name  string
+		prime func(cfg *config.Config) *config.Config // returns the config of the second run
+	}{
+		{
+			name: "vars changed",
+			prime: func(cfg *config.Config) *config.Config {
+				changed := *cfg
+				changed.Vars = map[string]interface{}{"owner": "someone-else"}
+				return &changed
+			},
+		},
+		{
+			name: "entry expired",
+			prime: func(cfg *config.Config) *config.Config {
+				key, err := payloadCacheKey("test-plugin", "1.2.3", cfg.ServiceName, cfg.Vars)
+				if err != nil {
+					t.Fatal(err)
+				}
+				entryPath := path.Join(cfg.PayloadCacheDirectory, key+".json")
+				var entry payloadFixture
+				data, _ := os.ReadFile(entryPath)
+				if err := json.Unmarshal(data, &entry); err != nil {
+					t.Fatalf("reading cache entry: %v", err)
+				}
+				entry.RecordedAt = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+				if err := entry.write(entryPath); err != nil {
+					t.Fatal(err)
+				}
+				return cfg
+			},
+		},
+		{
+			name: "cache turned off",
+			prime: func(cfg *config.Config) *config.Config {
+				off := *cfg
+				off.PayloadCacheTTL = 0
+				return &off
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cacheConfig(t, map[string]interface{}{"owner": "org"})
+			loader := func(*config.Config) (any, error) { return testPayload{Repo: "org/repo"}, nil }
+			if err := cachedOrchestrator(cfg, loader).loadPayload(context.Background()); err != nil {
+				t.Fatalf("priming the cache failed: %v", err)
+			}
+
+			calls := 0
+			second := cachedOrchestrator(tt.prime(cfg), func(*config.Config) (any, error) {
+				calls++
+				return testPayload{Repo: "org/fresh"}, nil
+			})
+			if err := second.loadPayload(context.Background()); err != nil {
+				t.Fatalf("second load failed: %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("expected the loader to run on a cache miss, ran %d times", calls)
+			}
+			if got, _ := second.Payload.(testPayload); got.Repo != "org/fresh" {
+				t.Errorf("payload = %#v, want the freshly loaded one", second.Payload)
+			}
+		})
+	}
+}
+
+func TestPayloadCacheKey(t *testing.T) {
+	base, err := payloadCacheKey("plugin", "1.0.0", "svc", map[string]any{"a": 1, "b": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reordered, _ := payloadCacheKey("plugin", "1.0.0", "svc", map[string]any{"b": "x", "a": 1})
+	if reordered != base {
+		t.Error("expected the key not to depend on map order")
+	}
+
+	tests := []struct {
+		name                 string
+		plugin, version, svc string
+		vars                 map[string]any
+	}{
+		{name: "plugin", plugin: "other", version: "1.0.0", svc: "svc", vars: map[string]any{"a": 1, "b": "x"}},
+		{name: "version", plugin: "plugin", version: "1.0.1", svc: "svc", vars: map[string]any{"a": 1, "b": "x"}},
+		{name: "service", plugin: "plugin", version: "1.0.0", svc: "other", vars: map[string]any{"a": 1, "b": "x"}},
+		{name: "vars", plugin: "plugin", version: "1.0.0", svc: "svc", vars: map[string]any{"a": 2, "b": "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := payloadCacheKey(tt.plugin, tt.version, tt.svc, tt.vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key == base {
+				t.Errorf("expected a different %s to change the key", tt.name)
+			}
+		})
+	}
+}
+
+func TestLoadPayload_CacheSkipsUnreadablePayloads(t *testing.T) {
+	tests := []struct {
+		name        string
+		payloadType reflect.Type
+		payload     any
+	}{
+		{name: "unknown type", payload: testPayload{Repo: "org/repo"}},
+		{name: "unexported field", payloadType: reflect.TypeFor[lossyPayload](), payload: lossyPayload{Repo: "org/repo", token: "secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cacheConfig(t, map[string]interface{}{"owner": "org"})
+			calls := 0
+			loader := func(*config.Config) (any, error) {
+				calls++
+				return tt.payload, nil
+			}
+			for range 2 {
+				v := cachedOrchestrator(cfg, loader)
+				v.possibleSuites[0].payloadType = tt.payloadType
+				if err := v.loadPayload(context.Background()); err != nil {
+					t.Fatalf("load failed: %v", err)
+				}
+			}
+			if calls != 2 {
+				t.Errorf("expected the loader to run on every load, ran %d times", calls)
+			}
+			entries, _ := os.ReadDir(cfg.PayloadCacheDirectory)
+			if len(entries) != 0 {
+				t.Errorf("expected no cache entry to be written, found %d", len(entries))
+			}
+		})
+	}
+}
+
+type lossyPayload struct {
+	Repo  string
+	token string
+}
+
+func TestLossyJSONField(t *testing.T) {
+	type nested struct {
+		Inner []map[string]*lossyPayload
+	}
+	type embedded struct {
+		testPayload
+		When time.Time
+	}
+	type skipped struct {
+		Secret string `json:"-"`
+	}
+	type untyped struct {
+		Data any
+	}
+	tests := []struct {
+		name string
+		t    reflect.Type
+		want string
+	}{
+		{name: "exported fields", t: reflect.TypeFor[testPayload]()},
+		{name: "embedded struct and json methods", t: reflect.TypeFor[embedded]()},
+		{name: "unexported field", t: reflect.TypeFor[lossyPayload](), want: "pluginkit.lossyPayload.token"},
+		{name: "nested unexported field", t: reflect.TypeFor[*nested](), want: "*pluginkit.nested.Inner.token"},
+		{name: "skipped field", t: reflect.TypeFor[skipped](), want: "pluginkit.skipped.Secret"},
+		{name: "interface field", t: reflect.TypeFor[untyped](), want: "pluginkit.untyped.Data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lossyJSONField(tt.t); got != tt.want {
+				t.Errorf("lossyJSONField(%v) = %q, want %q", tt.t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package pluginkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/privateerproj/privateer-sdk/utils"
+)
+
+// payloadFixture is the file written by record-payload and read by
+// replay-payload. Payloads are stored as JSON keyed by loader scope, the same
+// "orchestrator" or "suite:<catalog-id>" used in the benchmark report, so
+// only exported fields of the plugin's payload survive a round trip.
+type payloadFixture struct {
+	PluginName    string                     `json:"plugin-name"`
+	PluginVersion string                     `json:"plugin-version,omitempty"`
+	ServiceName   string                     `json:"service-name"`
+	RecordedAt    string                     `json:"recorded-at"`
+	Payloads      map[string]json.RawMessage `json:"payloads"`
+
+	path string // where a replayed fixture was read from
+}
+
+func (v *EvaluationOrchestrator) newPayloadFixture() *payloadFixture {
+	return &payloadFixture{
+		PluginName:    v.PluginName,
+		PluginVersion: v.PluginVersion,
+		ServiceName:   v.config.ServiceName,
+		RecordedAt:    time.Now().UTC().Format(time.RFC3339),
+		Payloads:      make(map[string]json.RawMessage),
+	}
+}
+
+func readPayloadFixture(path string) (*payloadFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading payload fixture: %w", err)
+	}
+	fixture := &payloadFixture{path: path}
+	if err := json.Unmarshal(data, fixture); err != nil {
+		return nil, fmt.Errorf("decoding payload fixture %s: %w", path, err)
+	}
+	if fixture.Payloads == nil {
+		fixture.Payloads = make(map[string]json.RawMessage)
+	}
+	return fixture, nil
+}
+
+// orchestratorPayloadType is the type a replayed orchestrator payload decodes
+// into: the one given to SetPayloadType, else the payload type of a typed
+// suite that shares the orchestrator payload.
+func (v *EvaluationOrchestrator) orchestratorPayloadType() reflect.Type {
+	if v.payloadType != nil {
+		return v.payloadType
+	}
+	for _, suite := range v.possibleSuites {
+		if suite.loader == nil && suite.payloadType != nil {
+			return suite.payloadType
+		}
+	}
+	return nil
+}
+
+func (f *payloadFixture) record(scope string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("recording %s payload: %w", scope, err)
+	}
+	f.Payloads[scope] = data
+	return nil
+}
+
+// replay decodes the payload recorded for scope into a new value of
+// payloadType, so typed steps receive the type they were registered with.
+func (f *payloadFixture) replay(scope string, payloadType reflect.Type) (any, error) {
+	data, ok := f.Payloads[scope]
+	if !ok {
+		recorded := make([]string, 0, len(f.Payloads))
+		for s := range f.Payloads {
+			recorded = append(recorded, s)
+		}
+		sort.Strings(recorded)
+		return nil, fmt.Errorf("payload fixture %s has no %s payload (recorded: %s)", f.path, scope, strings.Join(recorded, ", "))
+	}
+	if payloadType == nil {
+		return nil, fmt.Errorf("cannot replay the %s payload: its type is unknown; register the suite with typed steps or call SetPayloadType", scope)
+	}
+	value := reflect.New(payloadType)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("decoding %s payload into %s: %w", scope, payloadType, err)
+	}
+	return value.Elem().Interface(), nil
+}
+
+// write saves the fixture owner-readable only, since payloads often hold
+// data fetched with the service's credentials.
+func (f *payloadFixture) write(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding payload fixture: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, utils.DirPermissions); err != nil {
+			return fmt.Errorf("writing payload fixture: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing payload fixture: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package pluginkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+func failingLoader(*config.Config) (any, error) {
+	return nil, errors.New("loader must not run during replay")
+}
+
+// fixtureOrchestrator has an orchestrator loader shared by a typed suite, and
+// a second suite with its own loader returning a pointer payload.
+func fixtureOrchestrator(cfg *config.Config, loader, suiteLoader DataLoader) *EvaluationOrchestrator {
+	catalog := getTestCatalogWithRequirements()
+	return &EvaluationOrchestrator{
+		PluginName:    "test-plugin",
+		PluginVersion: "1.2.3",
+		config:        cfg,
+		loader:        loader,
+		possibleSuites: []*EvaluationSuite{
+			{CatalogId: "CCC.ObjStor", catalog: catalog, config: cfg, payloadType: reflect.TypeFor[testPayload]()},
+			{CatalogId: "CCC.Other", catalog: catalog, config: cfg, loader: suiteLoader, payloadType: reflect.TypeFor[*testPayload]()},
+		},
+	}
+}
+
+func TestLoadPayload_RecordThenReplay(t *testing.T) {
+	fixturePath := path.Join(t.TempDir(), "nested", "fixture.json")
+
+	recordCfg := setBasicConfig()
+	recordCfg.RecordPayload = fixturePath
+	recorder := fixtureOrchestrator(recordCfg,
+		func(*config.Config) (any, error) { return testPayload{Repo: "org/repo"}, nil },
+		func(*config.Config) (any, error) { return &testPayload{Repo: "org/other"}, nil },
+	)
+	if err := recorder.loadPayload(context.Background()); err != nil {
+		t.Fatalf("recording failed: %v", err)
+	}
+
+	info, err := os.Stat(fixturePath)
+	if err != nil {
+		t.Fatalf("expected a fixture at %s: %v", fixturePath, err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("fixture permissions = %v, want 0600", perm)
+	}
+	fixture, err := readPayloadFixture(fixturePath)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	if fixture.PluginName != "test-plugin" || fixture.PluginVersion != "1.2.3" || fixture.ServiceName != "test-service" {
+		t.Errorf("unexpected fixture header: %+v", fixture)
+	}
+
+	replayCfg := setBasicConfig()
+	replayCfg.ReplayPayload = fixturePath
+	replayer := fixtureOrchestrator(replayCfg, failingLoader, failingLoader)
+	if err := replayer.loadPayload(context.Background()); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+
+	if got, ok := replayer.Payload.(testPayload); !ok || got.Repo != "org/repo" {
+		t.Errorf("orchestrator payload = %#v, want testPayload{Repo: org/repo}", replayer.Payload)
+	}
+	if got, ok := replayer.possibleSuites[0].payload.(testPayload); !ok || got.Repo != "org/repo" {
+		t.Errorf("shared suite payload = %#v, want the orchestrator payload", replayer.possibleSuites[0].payload)
+	}
+	if got, ok := replayer.possibleSuites[1].payload.(*testPayload); !ok || got.Repo != "org/other" {
+		t.Errorf("suite payload = %#v, want &testPayload{Repo: org/other}", replayer.possibleSuites[1].payload)
+	}
+}
+
+func TestLoadPayload_ReplayErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture := func(name, content string) string {
+		p := path.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name        string
+		fixture     string
+		payloadType any
+		wantErr     string
+	}{
+		{
+			name:    "missing file",
+			fixture: path.Join(dir, "absent.json"),
+			wantErr: "reading payload fixture",
+		},
+		{
+			name:    "not json",
+			fixture: writeFixture("bad.json", "not json"),
+			wantErr: "decoding payload fixture",
+		},
+		{
+			name:        "scope not recorded",
+			fixture:     writeFixture("empty.json", `{"payloads": {"suite:X": {}}}`),
+			payloadType: testPayload{},
+			wantErr:     "has no orchestrator payload (recorded: suite:X)",
+		},
+		{
+			name:    "unknown payload type",
+			fixture: writeFixture("untyped.json", `{"payloads": {"orchestrator": {"Repo": "x"}}}`),
+			wantErr: "call SetPayloadType",
+		},
+		{
+			name:        "payload does not fit the type",
+			fixture:     writeFixture("mismatch.json", `{"payloads": {"orchestrator": {"Repo": 7}}}`),
+			payloadType: testPayload{},
+			wantErr:     "decoding orchestrator payload into pluginkit.testPayload",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setBasicConfig()
+			cfg.ReplayPayload = tt.fixture
+			orchestrator := &EvaluationOrchestrator{PluginName: "test-plugin", config: cfg, loader: failingLoader}
+			if tt.payloadType != nil {
+				orchestrator.SetPayloadType(tt.payloadType)
+			}
+			err := orchestrator.loadPayload(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestBenchmark_Replay_MarksLoaderSource runs typed steps end to end against a
+// replayed payload and checks the benchmark report says where it came from.
+func TestBenchmark_Replay_MarksLoaderSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	fixturePath := path.Join(tmpDir, "fixture.json")
+	if err := os.WriteFile(fixturePath, []byte(`{"payloads": {"orchestrator": {"Repo": "org/repo"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := setBasicConfig()
+	cfg.Policy.ControlCatalogs = []string{"CCC.ObjStor"}
+	cfg.Write = false
+	cfg.WriteDirectory = tmpDir
+	cfg.Benchmark = true
+	cfg.ReplayPayload = fixturePath
+
+	var seen string
+	typed := map[string][]pluginTypedStep{
+		"CCC.Core.C01.TR01": {func(p testPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+			seen = p.Repo
+			return gemara.Passed, "ok", gemara.High
+		}},
+	}
+	adapted, names := adaptTypedSteps[pluginTypedStep, testPayload](typed)
+	orchestrator := benchmarkOrchestrator(cfg, adapted)
+	orchestrator.loader = failingLoader
+	orchestrator.possibleSuites[0].stepNames = names
+	orchestrator.possibleSuites[0].payloadType = reflect.TypeFor[testPayload]()
+
+	if err := orchestrator.Mobilize(); err != nil {
+		t.Fatalf("Mobilize failed: %v", err)
+	}
+	if seen != "org/repo" {
+		t.Errorf("step saw payload repo %q, want org/repo", seen)
+	}
+
+	data, err := os.ReadFile(path.Join(tmpDir, "test-service", BenchmarkFileName))
+	if err != nil {
+		t.Fatalf("expected benchmark report: %v", err)
+	}
+	var report BenchmarkReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("benchmark report is not valid JSON: %v", err)
+	}
+	if len(report.Loaders) != 1 || report.Loaders[0].Source != LoaderSourceReplay {
+		t.Errorf("expected one replayed loader timing, got %+v", report.Loaders)
+	}
+}
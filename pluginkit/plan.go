@@ -0,0 +1,195 @@
+package pluginkit
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// PlanCommand is the plugin subcommand that prints what a run would execute.
+// command.NewPluginCommands wires it onto every plugin, and `pvtr plan` execs
+// it on each configured service's plugin with --json.
+const PlanCommand = "plan"
+
+// Plan is what a run of the configured service would execute, resolved from
+// config, policy, applicability, filters and the selected test suite without
+// calling any loader or step.
+type Plan struct {
+	PluginName    string        `json:"plugin-name"`
+	PluginVersion string        `json:"plugin-version,omitempty"`
+	ServiceName   string        `json:"service-name"`
+	TestSuite     string        `json:"test-suite"`
+	Invasive      bool          `json:"invasive"`
+	Applicability []string      `json:"applicability"`
+	Catalogs      []CatalogPlan `json:"catalogs"`
+	// UnmatchedCatalogs are policy catalogs the plugin has no suite for.
+	UnmatchedCatalogs []string `json:"unmatched-catalogs,omitempty"`
+}
+
+// CatalogPlan is the plan for one evaluation suite.
+type CatalogPlan struct {
+	CatalogId    string            `json:"catalog-id"`
+	Requirements []RequirementPlan `json:"requirements"`
+	// NoSteps lists requirements that would run but have no registered steps,
+	// so they would be recorded as Unknown.
+	NoSteps []string     `json:"no-steps,omitempty"`
+	Changes []ChangePlan `json:"changes,omitempty"`
+}
+
+// RequirementPlan is whether one requirement would run, and with which steps.
+type RequirementPlan struct {
+	Control     string `json:"control"`
+	Requirement string `json:"requirement"`
+	// Runs is false when the policy, a filter or the test suite leaves the
+	// requirement out, or its applicability does not match; Reason says which.
+	Runs   bool     `json:"runs"`
+	Reason string   `json:"reason,omitempty"`
+	Steps  []string `json:"steps,omitempty"`
+}
+
+// ChangePlan is a change registered with AddChangeManager. Changes are only
+// applied when the service is invasive.
+type ChangePlan struct {
+	Name        string `json:"name"`
+	TargetName  string `json:"target-name,omitempty"`
+	Description string `json:"description"`
+	CanApply    bool   `json:"can-apply"`
+}
+
+// Plan resolves what Mobilize would execute for the configured service. It
+// reads config and the registered suites only: no loader, step or change runs.
+func (v *EvaluationOrchestrator) Plan() (*Plan, error) {
+	v.setupConfig()
+	if v.config.Error != nil {
+		return nil, BAD_CONFIG(v.config.Error, "pln10")
+	}
+	if v.PluginName == "" || v.config.ServiceName == "" {
+		return nil, EVALUATION_ORCHESTRATOR_NAMES_NOT_SET(v.config.ServiceName, v.PluginName, "pln20")
+	}
+	testSuite, err := v.selectTestSuite()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		PluginName:    v.PluginName,
+		PluginVersion: v.PluginVersion,
+		ServiceName:   v.config.ServiceName,
+		TestSuite:     v.TestSuite,
+		Invasive:      v.config.Invasive,
+		Applicability: v.config.Policy.Applicability,
+	}
+	for _, catalogId := range v.config.Policy.ControlCatalogs {
+		index := slices.IndexFunc(v.possibleSuites, func(s *EvaluationSuite) bool { return s.CatalogId == catalogId })
+		if index < 0 {
+			plan.UnmatchedCatalogs = append(plan.UnmatchedCatalogs, catalogId)
+			continue
+		}
+		suite := v.possibleSuites[index]
+		suite.testSuite, suite.testSuiteRequirements = v.TestSuite, testSuite
+		catalogPlan := suite.plan()
+		catalogPlan.Changes = planChanges(v.changeManagers[catalogId], v.config.Invasive)
+		plan.Catalogs = append(plan.Catalogs, catalogPlan)
+	}
+	return plan, nil
+}
+
+// plan walks the suite's catalog the way setupEvalLog does.
+func (e *EvaluationSuite) plan() CatalogPlan {
+	catalogPlan := CatalogPlan{CatalogId: e.CatalogId}
+	for _, control := range e.catalog.Controls {
+		for _, requirement := range control.AssessmentRequirements {
+			planned := RequirementPlan{Control: control.Id, Requirement: requirement.Id}
+			for i, step := range e.steps[requirement.Id] {
+				planned.Steps = append(planned.Steps, e.stepName(requirement.Id, i, step))
+			}
+			planned.Runs, planned.Reason = e.selects(control, requirement.Id)
+			if planned.Runs {
+				applicability := e.config.Policy.RequirementApplicability(e.CatalogId, requirement.Id, requirement.Applicability)
+				if !slices.ContainsFunc(applicability, func(a string) bool { return slices.Contains(e.config.Policy.Applicability, a) }) {
+					planned.Runs = false
+					planned.Reason = fmt.Sprintf("applicability %v does not match %v", applicability, e.config.Policy.Applicability)
+				}
+			}
+			if planned.Runs && len(planned.Steps) == 0 {
+				catalogPlan.NoSteps = append(catalogPlan.NoSteps, requirement.Id)
+			}
+			catalogPlan.Requirements = append(catalogPlan.Requirements, planned)
+		}
+	}
+	return catalogPlan
+}
+
+func planChanges(cm *ChangeManager, invasive bool) []ChangePlan {
+	if cm == nil {
+		return nil
+	}
+	var changes []ChangePlan
+	for name, change := range cm.Changes {
+		changes = append(changes, ChangePlan{
+			Name:        name,
+			TargetName:  change.TargetName,
+			Description: change.Description,
+			CanApply:    invasive,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// Render writes the plan as a human-readable table.
+func (p *Plan) Render(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Plan: %s service=%s test-suite=%s invasive=%t\n", p.PluginName, p.ServiceName, p.TestSuite, p.Invasive)
+	_, _ = fmt.Fprintf(tw, "Applicability: %s\n", strings.Join(p.Applicability, ", "))
+	for _, catalog := range p.Catalogs {
+		runs := 0
+		for _, requirement := range catalog.Requirements {
+			if requirement.Runs {
+				runs++
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "\nCatalog %s: %d of %d requirements run\n", catalog.CatalogId, runs, len(catalog.Requirements))
+		_, _ = fmt.Fprintln(tw, "REQUIREMENT\tCONTROL\tRUNS\tSTEPS / REASON")
+		for _, requirement := range catalog.Requirements {
+			detail := strings.Join(requirement.Steps, ", ")
+			if !requirement.Runs {
+				detail = requirement.Reason
+			} else if detail == "" {
+				detail = "(no steps registered)"
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", requirement.Requirement, requirement.Control, yesNo(requirement.Runs), detail)
+		}
+		if len(catalog.NoSteps) > 0 {
+			_, _ = fmt.Fprintf(tw, "Requirements with no steps (recorded as Unknown): %s\n", strings.Join(catalog.NoSteps, ", "))
+		}
+		for _, change := range catalog.Changes {
+			state := "would not be applied (service is not invasive)"
+			if change.CanApply {
+				state = "may be applied"
+			}
+			_, _ = fmt.Fprintf(tw, "Change %s on %s: %s — %s\n", change.Name, orUnset(change.TargetName), change.Description, state)
+		}
+	}
+	if len(p.UnmatchedCatalogs) > 0 {
+		_, _ = fmt.Fprintf(tw, "\nCatalogs with no suite in this plugin: %s\n", strings.Join(p.UnmatchedCatalogs, ", "))
+	}
+	_ = tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func orUnset(s string) string {
+	if s == "" {
+		return "(target set when applied)"
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package pluginkit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+func TestEvaluationOrchestrator_Plan(t *testing.T) {
+	var ran bool
+	step_Record := func(interface{}) (gemara.Result, string, gemara.ConfidenceLevel) {
+		ran = true
+		return gemara.Passed, "", gemara.High
+	}
+	catalog := getTestCatalogWithControls(4)
+	catalog.Controls[3].AssessmentRequirements[0].Applicability = []string{"tlp-red"}
+
+	cfg := setBasicConfig()
+	cfg.Policy.ControlCatalogs = []string{catalog.Metadata.Id, "CCC.Missing"}
+	cfg.Filter = config.Filter{Exclude: []string{"CCC.Core.C02"}}
+	orchestrator := &EvaluationOrchestrator{
+		PluginName: "test-plugin",
+		config:     cfg,
+		loader: func(*config.Config) (any, error) {
+			ran = true
+			return nil, nil
+		},
+	}
+	orchestrator.possibleSuites = []*EvaluationSuite{{
+		CatalogId: catalog.Metadata.Id,
+		catalog:   catalog,
+		config:    cfg,
+		steps: map[string][]gemara.AssessmentStep{
+			"CCC.Core.C00.TR01": {step_Record, step_Pass},
+			"CCC.Core.C03.TR01": {step_Record},
+		},
+		stepNames: map[string][]string{"CCC.Core.C00.TR01": {"checks.Record", "checks.Pass"}},
+	}}
+	cm := &ChangeManager{}
+	cm.AddChange("make-public", Change{Description: "Make the bucket public"})
+	orchestrator.AddChangeManager(catalog.Metadata.Id, cm)
+
+	plan, err := orchestrator.Plan()
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if ran {
+		t.Error("Plan must not call the loader or any step")
+	}
+	if plan.ServiceName != "test-service" || plan.TestSuite != config.DefaultTestSuite || plan.Invasive {
+		t.Errorf("unexpected plan header: %+v", plan)
+	}
+	if strings.Join(plan.UnmatchedCatalogs, ",") != "CCC.Missing" || len(plan.Catalogs) != 1 {
+		t.Fatalf("unexpected catalogs: matched %d, unmatched %v", len(plan.Catalogs), plan.UnmatchedCatalogs)
+	}
+
+	requirements := plan.Catalogs[0].Requirements
+	want := []struct {
+		runs   bool
+		reason string
+		steps  string
+	}{
+		{runs: true, steps: "checks.Record,checks.Pass"},
+		{runs: true},
+		{reason: `excluded by filter "CCC.Core.C02"`},
+		{reason: "applicability [tlp-red] does not match [tlp-green tlp-amber]", steps: "github.com/privateerproj/privateer-sdk/pluginkit.TestEvaluationOrchestrator_Plan.func1"},
+	}
+	for i, w := range want {
+		got := requirements[i]
+		if got.Runs != w.runs || got.Reason != w.reason || strings.Join(got.Steps, ",") != w.steps {
+			t.Errorf("%s: got runs=%v reason=%q steps=%v", got.Requirement, got.Runs, got.Reason, got.Steps)
+		}
+	}
+	if strings.Join(plan.Catalogs[0].NoSteps, ",") != "CCC.Core.C01.TR01" {
+		t.Errorf("NoSteps = %v", plan.Catalogs[0].NoSteps)
+	}
+	if changes := plan.Catalogs[0].Changes; len(changes) != 1 || changes[0].Name != "make-public" || changes[0].CanApply {
+		t.Errorf("unexpected changes: %+v", changes)
+	}
+
+	var out bytes.Buffer
+	plan.Render(&out)
+	for _, s := range []string{
+		"Catalog CCC.Parallel: 2 of 4 requirements run",
+		"Requirements with no steps (recorded as Unknown): CCC.Core.C01.TR01",
+		"Change make-public",
+		"would not be applied (service is not invasive)",
+		"Catalogs with no suite in this plugin: CCC.Missing",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("rendered plan is missing %q:\n%s", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package pluginkittest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+
+	"github.com/privateerproj/privateer-sdk/pluginkit"
+)
+
+// Assessment returns the logged assessment of a requirement from the last
+// run, or nil if no suite ran it. When several suites assess the same
+// requirement, the first suite's assessment is returned.
+func Assessment(v *pluginkit.EvaluationOrchestrator, requirementId string) *gemara.AssessmentLog {
+	for _, suite := range v.Evaluation_Suites {
+		for _, evaluation := range suite.EvaluationLog.Evaluations {
+			if evaluation == nil {
+				continue
+			}
+			for _, assessment := range evaluation.AssessmentLogs {
+				if assessment != nil && assessment.Requirement.EntryId == requirementId {
+					return assessment
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// AssertResult reports an error unless the requirement's result is want.
+func AssertResult(t testing.TB, v *pluginkit.EvaluationOrchestrator, requirementId string, want gemara.Result) {
+	t.Helper()
+	if assessment := assessment(t, v, requirementId); assessment != nil && assessment.Result != want {
+		t.Errorf("%s: result = %s, want %s (message: %q)", requirementId, assessment.Result, want, assessment.Message)
+	}
+}
+
+// AssertMessage reports an error unless the requirement's message contains want.
+func AssertMessage(t testing.TB, v *pluginkit.EvaluationOrchestrator, requirementId, want string) {
+	t.Helper()
+	if assessment := assessment(t, v, requirementId); assessment != nil && !strings.Contains(assessment.Message, want) {
+		t.Errorf("%s: message = %q, want it to contain %q", requirementId, assessment.Message, want)
+	}
+}
+
+// AssertConfidence reports an error unless the requirement's confidence level is want.
+func AssertConfidence(t testing.TB, v *pluginkit.EvaluationOrchestrator, requirementId string, want gemara.ConfidenceLevel) {
+	t.Helper()
+	if assessment := assessment(t, v, requirementId); assessment != nil && assessment.ConfidenceLevel != want {
+		t.Errorf("%s: confidence = %s, want %s", requirementId, assessment.ConfidenceLevel, want)
+	}
+}
+
+// assessment is Assessment, reporting an error when the requirement did not run.
+func assessment(t testing.TB, v *pluginkit.EvaluationOrchestrator, requirementId string) *gemara.AssessmentLog {
+	t.Helper()
+	found := Assessment(v, requirementId)
+	if found == nil {
+		t.Errorf("%s: no assessment was logged for this requirement", requirementId)
+	}
+	return found
+}
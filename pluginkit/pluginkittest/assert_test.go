@@ -0,0 +1,84 @@
+package pluginkittest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+
+	"github.com/privateerproj/privateer-sdk/pluginkit"
+)
+
+// recorder captures the errors an assertion reports instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	cfg := NewConfig(t, []string{"TEST.Inline"}, []string{"tlp_green"}, nil)
+	v := NewOrchestrator(t, cfg, ParseCatalog(t, inlineCatalog))
+	v.AddLoader(StaticLoader(repoPayload{Private: true}))
+	steps := map[string][]repoStep{"TEST.Inline.C01.TR01": {step_Private}}
+	if err := pluginkit.AddEvaluationSuiteTyped(v, "TEST.Inline", nil, steps); err != nil {
+		t.Fatalf("registering suite: %v", err)
+	}
+	Mobilize(t, v)
+
+	tests := []struct {
+		name    string
+		assert  func(testing.TB)
+		wantErr string
+	}{
+		{
+			name:   "result matches",
+			assert: func(tb testing.TB) { AssertResult(tb, v, "TEST.Inline.C01.TR01", gemara.Passed) },
+		},
+		{
+			name:    "result differs",
+			assert:  func(tb testing.TB) { AssertResult(tb, v, "TEST.Inline.C01.TR01", gemara.Failed) },
+			wantErr: "result = Passed, want Failed",
+		},
+		{
+			name:   "message matches",
+			assert: func(tb testing.TB) { AssertMessage(tb, v, "TEST.Inline.C01.TR01", "private") },
+		},
+		{
+			name:    "message differs",
+			assert:  func(tb testing.TB) { AssertMessage(tb, v, "TEST.Inline.C01.TR01", "public") },
+			wantErr: `want it to contain "public"`,
+		},
+		{
+			name:    "confidence differs",
+			assert:  func(tb testing.TB) { AssertConfidence(tb, v, "TEST.Inline.C01.TR01", gemara.Low) },
+			wantErr: "confidence = High, want Low",
+		},
+		{
+			name:    "requirement did not run",
+			assert:  func(tb testing.TB) { AssertResult(tb, v, "TEST.Inline.C99.TR01", gemara.Passed) },
+			wantErr: "no assessment was logged",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			tt.assert(r)
+			if tt.wantErr == "" {
+				if len(r.errors) > 0 {
+					t.Errorf("expected the assertion to pass, got %v", r.errors)
+				}
+				return
+			}
+			if len(r.errors) != 1 || !strings.Contains(r.errors[0], tt.wantErr) {
+				t.Errorf("expected one error containing %q, got %v", tt.wantErr, r.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package pluginkittest helps plugin authors unit-test their assessment steps
+// by running them through a real EvaluationOrchestrator, without viper state,
+// a config file, or results written to disk:
+//
+//	cfg := pluginkittest.NewConfig(t, []string{"CCC.ObjStor"}, []string{"tlp-green"}, nil)
+//	v := pluginkittest.NewOrchestratorFS(t, cfg, "data/catalogs", catalogFiles)
+//	v.AddLoader(pluginkittest.StaticLoader(data.Payload{Public: true}))
+//	_ = pluginkit.AddEvaluationSuiteTyped(v, "CCC.ObjStor", nil, steps)
+//	pluginkittest.Mobilize(t, v)
+//	pluginkittest.AssertResult(t, v, "CCC.ObjStor.C01.TR01", gemara.Failed)
+package pluginkittest
+
+import (
+	"embed"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/privateerproj/privateer-sdk/config"
+	"github.com/privateerproj/privateer-sdk/pluginkit"
+)
+
+// ServiceName and PluginName are the names a test run is recorded under.
+const (
+	ServiceName = "test-service"
+	PluginName  = "test-plugin"
+)
+
+// NewConfig returns an in-memory config for a run of ServiceName against the
+// given catalogs and applicability. Nothing is read from viper, results are
+// not written, and log output is discarded. The write, payload cache and
+// change journal directories are temporary directories of t, so an invasive
+// run or a cache turned on leaves nothing behind. Adjust the returned fields
+// (e.g. Invasive, Filter or TestSuite) before running.
+func NewConfig(t testing.TB, catalogs, applicability []string, vars map[string]any) *config.Config {
+	t.Helper()
+	if vars == nil {
+		vars = make(map[string]any)
+	}
+	return &config.Config{
+		ServiceName:       ServiceName,
+		LogLevel:          "off",
+		Logger:            hclog.NewNullLogger(),
+		Write:             false,
+		WriteDirectory:    t.TempDir(),
+		Output:            []string{"yaml"},
+		OutputDestination: config.FileDestination,
+		JUnitUnresolved:   "error",
+		TestSuite:         config.DefaultTestSuite,
+		Policy: config.Policy{
+			ControlCatalogs: catalogs,
+			Applicability:   applicability,
+		},
+		Vars:                   vars,
+		PayloadCacheDirectory:  t.TempDir(),
+		ChangeJournalDirectory: t.TempDir(),
+	}
+}
+
+// NewOrchestrator returns an orchestrator named PluginName that runs with cfg
+// and has the given catalogs loaded as reference catalogs. Register loaders
+// and suites on it as the plugin would.
+func NewOrchestrator(t testing.TB, cfg *config.Config, catalogs ...*gemara.ControlCatalog) *pluginkit.EvaluationOrchestrator {
+	t.Helper()
+	v := &pluginkit.EvaluationOrchestrator{PluginName: PluginName}
+	v.SetConfig(cfg)
+	for _, catalog := range catalogs {
+		if err := v.AddReferenceCatalog(catalog); err != nil {
+			t.Fatalf("adding catalog: %v", err)
+		}
+	}
+	return v
+}
+
+// NewOrchestratorFS is NewOrchestrator with the catalogs read from dataDir in
+// files, typically the same embed.FS the plugin ships its catalogs in.
+func NewOrchestratorFS(t testing.TB, cfg *config.Config, dataDir string, files embed.FS) *pluginkit.EvaluationOrchestrator {
+	t.Helper()
+	v := NewOrchestrator(t, cfg)
+	if err := v.AddReferenceCatalogs(dataDir, files); err != nil {
+		t.Fatalf("loading catalogs from %s: %v", dataDir, err)
+	}
+	return v
+}
+
+// ParseCatalog parses an inline YAML control catalog for NewOrchestrator.
+func ParseCatalog(t testing.TB, data string) *gemara.ControlCatalog {
+	t.Helper()
+	var catalog gemara.ControlCatalog
+	if err := yaml.Unmarshal([]byte(data), &catalog); err != nil {
+		t.Fatalf("parsing catalog: %v", err)
+	}
+	return &catalog
+}
+
+// StaticLoader returns a DataLoader that always returns payload, for testing
+// steps against a fixed payload instead of a live API.
+func StaticLoader(payload any) pluginkit.DataLoader {
+	return func(*config.Config) (any, error) {
+		return payload, nil
+	}
+}
+
+// Mobilize runs the orchestrator and fails the test if the run errors. With a
+// config from NewConfig no results are written.
+func Mobilize(t testing.TB, v *pluginkit.EvaluationOrchestrator) {
+	t.Helper()
+	if err := v.Mobilize(); err != nil {
+		t.Fatalf("Mobilize failed: %v", err)
+	}
+}
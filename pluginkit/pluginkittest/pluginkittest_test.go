@@ -0,0 +1,121 @@
+package pluginkittest
+
+import (
+	"embed"
+	"os"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+
+	"github.com/privateerproj/privateer-sdk/pluginkit"
+)
+
+//go:embed testdata/catalogs
+var catalogFiles embed.FS
+
+const inlineCatalog = `
+metadata:
+  id: TEST.Inline
+title: Inline Test Catalog
+controls:
+  - id: TEST.Inline.C01
+    title: Inline control
+    objective: Exercise an inline catalog.
+    assessment-requirements:
+      - id: TEST.Inline.C01.TR01
+        text: The repository MUST be private.
+        applicability:
+          - tlp_green
+`
+
+// repoPayload stands in for a plugin's payload type.
+type repoPayload struct {
+	Private          bool
+	ForcePushAllowed bool
+}
+
+type repoStep func(repoPayload) (gemara.Result, string, gemara.ConfidenceLevel)
+
+func step_Private(p repoPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+	if !p.Private {
+		return gemara.Failed, "repository is public", gemara.High
+	}
+	return gemara.Passed, "repository is private", gemara.High
+}
+
+func step_PullRequests(repoPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+	return gemara.Passed, "pull requests are required", gemara.Medium
+}
+
+func step_ForcePush(p repoPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+	if p.ForcePushAllowed {
+		return gemara.Failed, "force pushes are allowed", gemara.High
+	}
+	return gemara.Passed, "force pushes are blocked", gemara.High
+}
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig(t, []string{"TEST.Inline"}, []string{"tlp_green"}, nil)
+	if cfg.Error != nil {
+		t.Fatalf("expected no config error, got %v", cfg.Error)
+	}
+	if cfg.ServiceName != ServiceName || cfg.Write || cfg.Logger == nil || cfg.Vars == nil {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	for name, dir := range map[string]string{
+		"write":          cfg.WriteDirectory,
+		"payload cache":  cfg.PayloadCacheDirectory,
+		"change journal": cfg.ChangeJournalDirectory,
+	} {
+		if info, err := os.Stat(dir); dir == "" || err != nil || !info.IsDir() {
+			t.Errorf("expected the %s directory to be a temporary directory, got %q (%v)", name, dir, err)
+		}
+	}
+}
+
+func TestMobilize_InlineCatalog(t *testing.T) {
+	writeDir := t.TempDir()
+	cfg := NewConfig(t, []string{"TEST.Inline"}, []string{"tlp_green"}, nil)
+	cfg.WriteDirectory = writeDir
+
+	v := NewOrchestrator(t, cfg, ParseCatalog(t, inlineCatalog))
+	v.AddLoader(StaticLoader(repoPayload{Private: false}))
+	steps := map[string][]repoStep{"TEST.Inline.C01.TR01": {step_Private}}
+	if err := pluginkit.AddEvaluationSuiteTyped(v, "TEST.Inline", nil, steps); err != nil {
+		t.Fatalf("registering suite: %v", err)
+	}
+
+	Mobilize(t, v)
+
+	AssertResult(t, v, "TEST.Inline.C01.TR01", gemara.Failed)
+	AssertMessage(t, v, "TEST.Inline.C01.TR01", "public")
+	AssertConfidence(t, v, "TEST.Inline.C01.TR01", gemara.High)
+
+	entries, err := os.ReadDir(writeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected nothing written to %s, found %d entries", writeDir, len(entries))
+	}
+}
+
+func TestMobilize_EmbeddedCatalogs(t *testing.T) {
+	cfg := NewConfig(t, []string{"TEST.Repo"}, []string{"tlp_green"}, nil)
+	v := NewOrchestratorFS(t, cfg, "testdata/catalogs", catalogFiles)
+	v.AddLoader(StaticLoader(repoPayload{ForcePushAllowed: true}))
+	steps := map[string][]repoStep{
+		"TEST.Repo.C01.TR01": {step_PullRequests},
+		"TEST.Repo.C01.TR02": {step_ForcePush},
+	}
+	if err := pluginkit.AddEvaluationSuiteTyped(v, "TEST.Repo", nil, steps); err != nil {
+		t.Fatalf("registering suite: %v", err)
+	}
+
+	Mobilize(t, v)
+
+	AssertResult(t, v, "TEST.Repo.C01.TR01", gemara.Passed)
+	AssertConfidence(t, v, "TEST.Repo.C01.TR01", gemara.Medium)
+	AssertResult(t, v, "TEST.Repo.C01.TR02", gemara.Failed)
+	AssertMessage(t, v, "TEST.Repo.C01.TR02", "force pushes are allowed")
+}
@@ -0,0 +1,238 @@
+package pluginkit
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// AddPrerequisites declares requirements of a registered suite that only make
+// sense once others have passed, e.g. that branch protection is only checked
+// if the token has admin scope. prerequisites maps a requirement id to the
+// ids it depends on. The suite then evaluates in dependency order, and a
+// requirement whose prerequisite did not pass is recorded as not run, with
+// the prerequisite named in its message.
+//
+// Every id must be a requirement of the suite's catalog, and the
+// dependencies may not form a cycle, either between requirements or between
+// the controls that hold them, since controls are evaluated as a unit.
+// Repeated calls add to the suite's prerequisites.
+func (v *EvaluationOrchestrator) AddPrerequisites(catalogId string, prerequisites map[string][]string) error {
+	index := slices.IndexFunc(v.possibleSuites, func(s *EvaluationSuite) bool { return s.CatalogId == catalogId })
+	if index < 0 {
+		return BAD_PREREQUISITES(v.PluginName, fmt.Sprintf("no evaluation suite is registered for catalog %s", catalogId), "apr10")
+	}
+	suite := v.possibleSuites[index]
+
+	controlOf := make(map[string]string)
+	for _, control := range suite.catalog.Controls {
+		for _, requirement := range control.AssessmentRequirements {
+			controlOf[requirement.Id] = control.Id
+		}
+	}
+
+	merged := make(map[string][]string, len(suite.prerequisites)+len(prerequisites))
+	for id, deps := range suite.prerequisites {
+		merged[id] = slices.Clone(deps)
+	}
+	for id, deps := range prerequisites {
+		for _, dep := range append([]string{id}, deps...) {
+			if _, ok := controlOf[dep]; !ok {
+				return BAD_PREREQUISITES(v.PluginName, fmt.Sprintf("%s is not a requirement of catalog %s", dep, catalogId), "apr20")
+			}
+		}
+		for _, dep := range deps {
+			if !slices.Contains(merged[id], dep) {
+				merged[id] = append(merged[id], dep)
+			}
+		}
+	}
+
+	if cycle := findCycle(merged); cycle != nil {
+		return BAD_PREREQUISITES(v.PluginName, fmt.Sprintf("requirements depend on each other: %s", strings.Join(cycle, " -> ")), "apr30")
+	}
+	if cycle := findCycle(controlDependencies(merged, controlOf)); cycle != nil {
+		return BAD_PREREQUISITES(v.PluginName, fmt.Sprintf("controls depend on each other through their requirements: %s", strings.Join(cycle, " -> ")), "apr40")
+	}
+
+	suite.prerequisites = merged
+	return nil
+}
+
+// controlDependencies lifts requirement prerequisites to the controls that
+// hold the requirements, ignoring dependencies within one control.
+func controlDependencies(prerequisites map[string][]string, controlOf map[string]string) map[string][]string {
+	controls := make(map[string][]string)
+	for id, deps := range prerequisites {
+		for _, dep := range deps {
+			from, to := controlOf[id], controlOf[dep]
+			if from != to && !slices.Contains(controls[from], to) {
+				controls[from] = append(controls[from], to)
+			}
+		}
+	}
+	return controls
+}
+
+// findCycle returns a dependency cycle as a path that starts and ends with
+// the same id, or nil when there is none. Ids are visited in sorted order so
+// the reported cycle is stable.
+func findCycle(dependsOn map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range dependsOn[id] {
+			switch state[dep] {
+			case visiting:
+				start := slices.Index(path, dep)
+				return append(slices.Clone(path[start:]), dep)
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+
+	ids := make([]string, 0, len(dependsOn))
+	for id := range dependsOn {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// dependencyOrder returns ids with each placed after the ids it depends on,
+// otherwise keeping the given order. Dependencies outside ids are ignored;
+// AddPrerequisites has already rejected cycles.
+func dependencyOrder(ids []string, dependsOn func(id string) []string) []string {
+	pending := slices.Clone(ids)
+	ordered := make([]string, 0, len(ids))
+	for len(pending) > 0 {
+		next := slices.IndexFunc(pending, func(id string) bool {
+			return !slices.ContainsFunc(dependsOn(id), func(dep string) bool { return dep != id && slices.Contains(pending, dep) })
+		})
+		if next < 0 {
+			next = 0 // unreachable without a cycle; keep the given order
+		}
+		ordered = append(ordered, pending[next])
+		pending = slices.Delete(pending, next, next+1)
+	}
+	return ordered
+}
+
+// orderByPrerequisites puts each control's assessments in dependency order,
+// so a prerequisite in the same control runs before its dependents.
+func (e *EvaluationSuite) orderByPrerequisites() {
+	if len(e.prerequisites) == 0 {
+		return
+	}
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		byId := make(map[string]*gemara.AssessmentLog, len(evaluation.AssessmentLogs))
+		ids := make([]string, 0, len(evaluation.AssessmentLogs))
+		for _, assessment := range evaluation.AssessmentLogs {
+			byId[assessment.Requirement.EntryId] = assessment
+			ids = append(ids, assessment.Requirement.EntryId)
+		}
+		for i, id := range dependencyOrder(ids, func(id string) []string { return e.prerequisites[id] }) {
+			evaluation.AssessmentLogs[i] = byId[id]
+		}
+	}
+}
+
+// evaluateInDependencyOrder runs every control evaluation with the controls
+// holding prerequisites first, and reports whether it did so. Without
+// prerequisites the evaluations run in catalog order as usual.
+func (e *EvaluationSuite) evaluateInDependencyOrder() bool {
+	if len(e.prerequisites) == 0 {
+		return false
+	}
+	controlOf := make(map[string]string)
+	byControl := make(map[string]*gemara.ControlEvaluation)
+	var controlIds []string
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		byControl[evaluation.Control.EntryId] = evaluation
+		controlIds = append(controlIds, evaluation.Control.EntryId)
+		for _, assessment := range evaluation.AssessmentLogs {
+			controlOf[assessment.Requirement.EntryId] = evaluation.Control.EntryId
+		}
+	}
+	controlDeps := controlDependencies(e.prerequisites, controlOf)
+	for _, controlId := range dependencyOrder(controlIds, func(id string) []string { return controlDeps[id] }) {
+		byControl[controlId].Evaluate(e.payload, e.config.Policy.Applicability)
+		if e.changeManager != nil && e.changeManager.CorruptedState {
+			break
+		}
+	}
+	return true
+}
+
+// gatedSteps makes each step of requirementId check its prerequisites first,
+// returning NotRun without running the step while one has not passed.
+func (e *EvaluationSuite) gatedSteps(requirementId string, steps []gemara.AssessmentStep) []gemara.AssessmentStep {
+	if len(e.prerequisites[requirementId]) == 0 || len(steps) == 0 {
+		return steps
+	}
+	gated := make([]gemara.AssessmentStep, len(steps))
+	for i, step := range steps {
+		gated[i] = func(payload any) (gemara.Result, string, gemara.ConfidenceLevel) {
+			if reason := e.unmetPrerequisite(requirementId); reason != "" {
+				return gemara.NotRun, reason, gemara.Undetermined
+			}
+			return step(payload)
+		}
+	}
+	return gated
+}
+
+// unmetPrerequisite explains why requirementId may not run yet, or returns ""
+// when every prerequisite passed.
+func (e *EvaluationSuite) unmetPrerequisite(requirementId string) string {
+	for _, id := range e.prerequisites[requirementId] {
+		prerequisite := e.assessment(id)
+		switch {
+		case prerequisite == nil:
+			return fmt.Sprintf("prerequisite %s was not assessed", id)
+		case prerequisite.Result == gemara.Passed:
+			continue
+		case prerequisite.Result == gemara.Failed:
+			return fmt.Sprintf("prerequisite %s failed", id)
+		default:
+			return fmt.Sprintf("prerequisite %s did not pass: %s", id, prerequisite.Result)
+		}
+	}
+	return ""
+}
+
+// assessment returns the suite's logged assessment of requirementId.
+func (e *EvaluationSuite) assessment(requirementId string) *gemara.AssessmentLog {
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		for _, assessment := range evaluation.AssessmentLogs {
+			if assessment.Requirement.EntryId == requirementId {
+				return assessment
+			}
+		}
+	}
+	return nil
+}
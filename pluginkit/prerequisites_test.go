@@ -0,0 +1,248 @@
+package pluginkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// prerequisiteCatalog has two controls of two requirements each.
+func prerequisiteCatalog() *gemara.ControlCatalog {
+	catalog := &gemara.ControlCatalog{Metadata: gemara.Metadata{Id: "TEST.Prereq"}}
+	for _, controlId := range []string{"C01", "C02"} {
+		control := gemara.Control{Id: controlId, Title: controlId, Objective: "objective of " + controlId}
+		for _, tr := range []string{".TR01", ".TR02"} {
+			control.AssessmentRequirements = append(control.AssessmentRequirements, gemara.AssessmentRequirement{
+				Id: controlId + tr, Text: "text", Applicability: requestedApplicability,
+			})
+		}
+		catalog.Controls = append(catalog.Controls, control)
+	}
+	return catalog
+}
+
+func prerequisiteOrchestrator(steps map[string][]gemara.AssessmentStep) *EvaluationOrchestrator {
+	cfg := setBasicConfig()
+	return &EvaluationOrchestrator{
+		PluginName: "test-plugin",
+		config:     cfg,
+		possibleSuites: []*EvaluationSuite{
+			{CatalogId: "TEST.Prereq", catalog: prerequisiteCatalog(), steps: steps, config: cfg},
+		},
+	}
+}
+
+func TestAddPrerequisites(t *testing.T) {
+	tests := []struct {
+		name      string
+		catalogId string
+		calls     []map[string][]string
+		wantErr   string
+		wantMod   string
+	}{
+		{
+			name:      "valid",
+			catalogId: "TEST.Prereq",
+			calls:     []map[string][]string{{"C01.TR01": {"C02.TR01"}}, {"C01.TR01": {"C02.TR02"}, "C02.TR02": {"C02.TR01"}}},
+		},
+		{
+			name:      "unknown suite",
+			catalogId: "TEST.Missing",
+			calls:     []map[string][]string{{"C01.TR01": {"C02.TR01"}}},
+			wantErr:   "no evaluation suite is registered for catalog TEST.Missing",
+			wantMod:   "apr10",
+		},
+		{
+			name:      "unknown requirement",
+			catalogId: "TEST.Prereq",
+			calls:     []map[string][]string{{"C01.TR01": {"C09.TR01"}}},
+			wantErr:   "C09.TR01 is not a requirement of catalog TEST.Prereq",
+			wantMod:   "apr20",
+		},
+		{
+			name:      "self dependency",
+			catalogId: "TEST.Prereq",
+			calls:     []map[string][]string{{"C01.TR01": {"C01.TR01"}}},
+			wantErr:   "requirements depend on each other: C01.TR01 -> C01.TR01",
+			wantMod:   "apr30",
+		},
+		{
+			name:      "cycle across calls",
+			catalogId: "TEST.Prereq",
+			calls:     []map[string][]string{{"C01.TR01": {"C01.TR02"}}, {"C01.TR02": {"C01.TR01"}}},
+			wantErr:   "requirements depend on each other: C01.TR01 -> C01.TR02 -> C01.TR01",
+			wantMod:   "apr30",
+		},
+		{
+			name:      "control cycle",
+			catalogId: "TEST.Prereq",
+			calls:     []map[string][]string{{"C01.TR01": {"C02.TR01"}, "C02.TR02": {"C01.TR02"}}},
+			wantErr:   "controls depend on each other through their requirements: C01 -> C02 -> C01",
+			wantMod:   "apr40",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := prerequisiteOrchestrator(nil)
+			var err error
+			for _, call := range tt.calls {
+				if err = v.AddPrerequisites(tt.catalogId, call); err != nil {
+					break
+				}
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := v.possibleSuites[0].prerequisites["C01.TR01"]; len(got) != 2 {
+					t.Errorf("expected prerequisites to merge across calls, got %v", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), tt.wantMod) {
+				t.Errorf("error = %v, want one containing %q and %q", err, tt.wantErr, tt.wantMod)
+			}
+			if !errors.Is(err, ErrDevBug) {
+				t.Errorf("expected a dev bug error, got %v", err)
+			}
+			if len(tt.calls) > 1 && len(v.possibleSuites[0].prerequisites["C01.TR02"]) != 0 {
+				t.Error("a rejected call must not change the registered prerequisites")
+			}
+		})
+	}
+}
+
+func TestEvaluationSuite_Prerequisites(t *testing.T) {
+	tests := []struct {
+		name          string
+		prerequisites map[string][]string
+		failing       string // the requirement whose step fails
+		wantResults   map[string]gemara.Result
+		wantMessages  map[string]string
+		wantOrder     []string // steps in the order they ran
+	}{
+		{
+			name:          "failed prerequisite in a later control",
+			prerequisites: map[string][]string{"C01.TR01": {"C02.TR01"}},
+			failing:       "C02.TR01",
+			wantResults:   map[string]gemara.Result{"C01.TR01": gemara.NotRun, "C02.TR01": gemara.Failed},
+			wantMessages:  map[string]string{"C01.TR01": "prerequisite C02.TR01 failed"},
+			wantOrder:     []string{"C02.TR01", "C01.TR02"},
+		},
+		{
+			name:          "passed prerequisite in the same control runs first",
+			prerequisites: map[string][]string{"C01.TR01": {"C01.TR02"}},
+			wantResults:   map[string]gemara.Result{"C01.TR01": gemara.Passed, "C01.TR02": gemara.Passed},
+			wantOrder:     []string{"C01.TR02", "C01.TR01", "C02.TR01", "C02.TR02"},
+		},
+		{
+			name:          "dependent skipped when the control halts",
+			prerequisites: map[string][]string{"C01.TR02": {"C01.TR01"}},
+			failing:       "C01.TR01",
+			wantResults:   map[string]gemara.Result{"C01.TR01": gemara.Failed, "C01.TR02": gemara.NotRun},
+			wantMessages:  map[string]string{"C01.TR02": "prerequisite C01.TR01 failed"},
+			wantOrder:     []string{"C01.TR01", "C02.TR01", "C02.TR02"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order []string
+			steps := make(map[string][]gemara.AssessmentStep)
+			for _, id := range []string{"C01.TR01", "C01.TR02", "C02.TR01", "C02.TR02"} {
+				steps[id] = []gemara.AssessmentStep{func(interface{}) (gemara.Result, string, gemara.ConfidenceLevel) {
+					order = append(order, id)
+					if id == tt.failing {
+						return gemara.Failed, "failed", gemara.High
+					}
+					return gemara.Passed, "passed", gemara.High
+				}}
+			}
+			v := prerequisiteOrchestrator(steps)
+			if err := v.AddPrerequisites("TEST.Prereq", tt.prerequisites); err != nil {
+				t.Fatalf("AddPrerequisites failed: %v", err)
+			}
+			suite := v.possibleSuites[0]
+			if err := suite.Evaluate("test-service"); err != nil {
+				t.Fatalf("Evaluate failed: %v", err)
+			}
+
+			if strings.Join(order, ",") != strings.Join(tt.wantOrder, ",") {
+				t.Errorf("steps ran in order %v, want %v", order, tt.wantOrder)
+			}
+			for id, want := range tt.wantResults {
+				if got := suite.assessment(id); got == nil || got.Result != want {
+					t.Errorf("%s: got %+v, want result %s", id, got, want)
+				}
+			}
+			for id, want := range tt.wantMessages {
+				if got := suite.assessment(id); got == nil || got.Message != want {
+					t.Errorf("%s: got %+v, want message %q", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluationSuite_PrerequisitesCorruptedState(t *testing.T) {
+	var order []string
+	cm := &ChangeManager{}
+	cm.AddChange("bucket-policy", badApplyChange())
+	cm.Allow()
+	steps := make(map[string][]gemara.AssessmentStep)
+	for _, id := range []string{"C01.TR01", "C01.TR02", "C02.TR01", "C02.TR02"} {
+		steps[id] = []gemara.AssessmentStep{func(interface{}) (gemara.Result, string, gemara.ConfidenceLevel) {
+			order = append(order, id)
+			if id == "C02.TR02" {
+				if applied, _ := cm.Apply("bucket-policy", "bucket", nil); !applied {
+					return gemara.Failed, "could not apply the bucket policy", gemara.High
+				}
+			}
+			return gemara.Passed, "passed", gemara.High
+		}}
+	}
+	v := prerequisiteOrchestrator(steps)
+	if err := v.AddPrerequisites("TEST.Prereq", map[string][]string{"C01.TR01": {"C02.TR01"}}); err != nil {
+		t.Fatalf("AddPrerequisites failed: %v", err)
+	}
+	suite := v.possibleSuites[0]
+	suite.config.Invasive = true
+	suite.AddChangeManager(cm)
+
+	err := suite.Evaluate("test-service")
+	if err == nil || !strings.Contains(err.Error(), "corrupted") {
+		t.Fatalf("expected the corrupted state to be reported, got %v", err)
+	}
+	if want := "C02.TR01,C02.TR02"; strings.Join(order, ",") != want {
+		t.Errorf("steps ran in order %v, want %s", order, want)
+	}
+	if got := suite.assessment("C01.TR01"); got == nil || got.Result != gemara.NotRun {
+		t.Errorf("expected the control after the corruption not to run, got %+v", got)
+	}
+	if suite.evalFailures != 1 || suite.evalSuccesses != 0 {
+		t.Errorf("expected the control that corrupted state counted as failed, got %d failed and %d passed", suite.evalFailures, suite.evalSuccesses)
+	}
+}
+
+func TestEvaluationSuite_PrerequisiteMessages(t *testing.T) {
+	steps := map[string][]gemara.AssessmentStep{
+		"C01.TR01": {step_Fail},
+		"C01.TR02": {step_Pass},
+		"C02.TR01": {step_Pass},
+		"C02.TR02": {step_Pass},
+	}
+	v := prerequisiteOrchestrator(steps)
+	// C01.TR02 is skipped when C01.TR01 fails, not for want of its prerequisite
+	if err := v.AddPrerequisites("TEST.Prereq", map[string][]string{"C01.TR02": {"C02.TR01"}}); err != nil {
+		t.Fatalf("AddPrerequisites failed: %v", err)
+	}
+	suite := v.possibleSuites[0]
+	if err := suite.Evaluate("test-service"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	if got := suite.assessment("C01.TR02"); got == nil || got.Result != gemara.NotRun || strings.Contains(got.Message, "prerequisite") {
+		t.Errorf("expected C01.TR02, skipped at its control's failure, not to name its passed prerequisite, got %+v", got)
+	}
+}
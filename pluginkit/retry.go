@@ -0,0 +1,105 @@
+package pluginkit
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// RetryPolicy reruns a step whose result suggests a transient failure, such
+// as an upstream API answering 502, before the result is recorded.
+type RetryPolicy struct {
+	// Attempts is the most times a step runs, including the first. Values
+	// below 2 disable retries.
+	Attempts int
+	// Backoff is the wait before the first retry. It doubles before each
+	// further retry.
+	Backoff time.Duration
+	// Retryable lists the results that are retried. Empty retries Unknown only.
+	Retryable []gemara.Result
+}
+
+// SetRetryPolicy sets the retry policy for every step of every suite.
+func (v *EvaluationOrchestrator) SetRetryPolicy(policy RetryPolicy) {
+	v.retryPolicy = policy
+}
+
+// SetRequirementRetryPolicy overrides the retry policy for the steps of one
+// requirement, e.g. to disable retries for a step that is not idempotent.
+func (v *EvaluationOrchestrator) SetRequirementRetryPolicy(requirementId string, policy RetryPolicy) {
+	if v.requirementRetries == nil {
+		v.requirementRetries = make(map[string]RetryPolicy)
+	}
+	v.requirementRetries[requirementId] = policy
+}
+
+func (p RetryPolicy) retries(result gemara.Result) bool {
+	if len(p.Retryable) == 0 {
+		return result == gemara.Unknown
+	}
+	return slices.Contains(p.Retryable, result)
+}
+
+// retryPolicyFor returns the policy applying to the steps of requirementId.
+func (e *EvaluationSuite) retryPolicyFor(requirementId string) RetryPolicy {
+	if policy, ok := e.requirementRetries[requirementId]; ok {
+		return policy
+	}
+	return e.retryPolicy
+}
+
+// retriedSteps wraps each step of requirementId in the retry policy. The
+// returned slice holds the attempts each step took once it has run, for the
+// step timings; both are unchanged or nil when the policy makes no retries.
+// A retried step's message is prefixed with the attempt it ended on, so the
+// retry is visible in the assessment log. Evidence an attempt added to
+// evidence is dropped when the step is retried, leaving only the evidence of
+// the attempt whose result is recorded.
+func (e *EvaluationSuite) retriedSteps(requirementId string, steps []gemara.AssessmentStep, evidence *[]gemara.Evidence) ([]gemara.AssessmentStep, []int) {
+	policy := e.retryPolicyFor(requirementId)
+	if policy.Attempts < 2 || len(steps) == 0 {
+		return steps, nil
+	}
+	attempts := make([]int, len(steps))
+	retried := make([]gemara.AssessmentStep, len(steps))
+	for i, step := range steps {
+		name := e.stepName(requirementId, i, step)
+		retried[i] = func(payload any) (gemara.Result, string, gemara.ConfidenceLevel) {
+			wait := policy.Backoff
+			collected := len(*evidence)
+			for attempt := 1; ; attempt++ {
+				result, message, confidence := step(payload)
+				attempts[i] = attempt
+				if attempt == policy.Attempts || !policy.retries(result) || !e.waitToRetry(wait) {
+					if attempt > 1 {
+						message = fmt.Sprintf("[attempt %d of %d] %s", attempt, policy.Attempts, message)
+					}
+					return result, message, confidence
+				}
+				e.config.Logger.Debug("retrying step", "requirement", requirementId, "step", name,
+					"attempt", attempt+1, "result", result, "message", singleLine(message))
+				*evidence = (*evidence)[:collected]
+				wait *= 2
+			}
+		}
+	}
+	return retried, attempts
+}
+
+// waitToRetry sleeps for the backoff, reporting false if the run ends first.
+func (e *EvaluationSuite) waitToRetry(wait time.Duration) bool {
+	ctx := e.runContext()
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
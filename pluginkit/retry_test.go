@@ -0,0 +1,219 @@
+package pluginkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+// flakyStep returns Unknown for its first failures calls, then final.
+func flakyStep(failures int, final gemara.Result) (gemara.AssessmentStep, *int) {
+	calls := 0
+	return func(interface{}) (gemara.Result, string, gemara.ConfidenceLevel) {
+		calls++
+		if calls <= failures {
+			return gemara.Unknown, "upstream returned 502", gemara.Undetermined
+		}
+		return final, "upstream answered", gemara.High
+	}, &calls
+}
+
+func TestRetriedSteps(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       RetryPolicy
+		override     *RetryPolicy
+		failures     int
+		final        gemara.Result
+		wantResult   gemara.Result
+		wantCalls    int
+		wantAttempts []int
+		wantMessage  string
+	}{
+		{
+			name:        "no policy",
+			failures:    1,
+			final:       gemara.Passed,
+			wantResult:  gemara.Unknown,
+			wantCalls:   1,
+			wantMessage: "upstream returned 502",
+		},
+		{
+			name:         "recovers on retry",
+			policy:       RetryPolicy{Attempts: 3},
+			failures:     1,
+			final:        gemara.Passed,
+			wantResult:   gemara.Passed,
+			wantCalls:    2,
+			wantAttempts: []int{2},
+			wantMessage:  "[attempt 2 of 3] upstream answered",
+		},
+		{
+			name:         "gives up after the last attempt",
+			policy:       RetryPolicy{Attempts: 2},
+			failures:     5,
+			final:        gemara.Passed,
+			wantResult:   gemara.Unknown,
+			wantCalls:    2,
+			wantAttempts: []int{2},
+			wantMessage:  "[attempt 2 of 2] upstream returned 502",
+		},
+		{
+			name:         "first attempt succeeds",
+			policy:       RetryPolicy{Attempts: 3},
+			final:        gemara.Failed,
+			wantResult:   gemara.Failed,
+			wantCalls:    1,
+			wantAttempts: []int{1},
+			wantMessage:  "upstream answered",
+		},
+		{
+			name:         "only listed results are retried",
+			policy:       RetryPolicy{Attempts: 3, Retryable: []gemara.Result{gemara.NeedsReview}},
+			failures:     1,
+			final:        gemara.Passed,
+			wantResult:   gemara.Unknown,
+			wantCalls:    1,
+			wantAttempts: []int{1},
+			wantMessage:  "upstream returned 502",
+		},
+		{
+			name:        "requirement override disables retries",
+			policy:      RetryPolicy{Attempts: 3},
+			override:    &RetryPolicy{},
+			failures:    1,
+			final:       gemara.Passed,
+			wantResult:  gemara.Unknown,
+			wantCalls:   1,
+			wantMessage: "upstream returned 502",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := &EvaluationSuite{config: setBasicConfig(), retryPolicy: tt.policy}
+			if tt.override != nil {
+				suite.requirementRetries = map[string]RetryPolicy{"CCC.Core.C01.TR01": *tt.override}
+			}
+			step, calls := flakyStep(tt.failures, tt.final)
+
+			steps, attempts := suite.retriedSteps("CCC.Core.C01.TR01", []gemara.AssessmentStep{step}, new([]gemara.Evidence))
+			result, message, _ := steps[0](nil)
+
+			if result != tt.wantResult || message != tt.wantMessage {
+				t.Errorf("got %s %q, want %s %q", result, message, tt.wantResult, tt.wantMessage)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("step ran %d times, want %d", *calls, tt.wantCalls)
+			}
+			if len(attempts) != len(tt.wantAttempts) || (len(attempts) > 0 && attempts[0] != tt.wantAttempts[0]) {
+				t.Errorf("attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetriedSteps_StopsWhenTheRunEnds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite := &EvaluationSuite{config: setBasicConfig(), runCtx: ctx, retryPolicy: RetryPolicy{Attempts: 5, Backoff: time.Hour}}
+	step, calls := flakyStep(5, gemara.Passed)
+	steps, _ := suite.retriedSteps("CCC.Core.C01.TR01", []gemara.AssessmentStep{step}, new([]gemara.Evidence))
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	done := make(chan gemara.Result, 1)
+	go func() {
+		result, _, _ := steps[0](nil)
+		done <- result
+	}()
+	select {
+	case result := <-done:
+		if result != gemara.Unknown || *calls != 1 {
+			t.Errorf("got %s after %d calls, want Unknown after 1", result, *calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry backoff did not stop when the run was cancelled")
+	}
+}
+
+// TestBenchmark_Retries_RecordsAttempts runs a flaky step end to end and checks
+// the attempts reach both the assessment log and the benchmark step timing.
+func TestBenchmark_Retries_RecordsAttempts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := setBasicConfig()
+	cfg.Policy.ControlCatalogs = []string{"CCC.ObjStor"}
+	cfg.Write = false
+	cfg.WriteDirectory = tmpDir
+	cfg.Benchmark = true
+
+	step, _ := flakyStep(2, gemara.Passed)
+	orchestrator := benchmarkOrchestrator(cfg, map[string][]gemara.AssessmentStep{"CCC.Core.C01.TR01": {step}})
+	orchestrator.SetRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	if err := orchestrator.Mobilize(); err != nil {
+		t.Fatalf("Mobilize failed: %v", err)
+	}
+
+	assessment := orchestrator.Evaluation_Suites[0].EvaluationLog.Evaluations[0].AssessmentLogs[0]
+	if assessment.Result != gemara.Passed || !strings.HasPrefix(assessment.Message, "[attempt 3 of 3]") {
+		t.Errorf("assessment = %s %q, want Passed on attempt 3", assessment.Result, assessment.Message)
+	}
+
+	data, err := os.ReadFile(path.Join(tmpDir, "test-service", BenchmarkFileName))
+	if err != nil {
+		t.Fatalf("expected benchmark report: %v", err)
+	}
+	var report BenchmarkReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("benchmark report is not valid JSON: %v", err)
+	}
+	if steps := report.Suites[0].Steps; len(steps) != 1 || steps[0].Attempts != 3 {
+		t.Errorf("expected one step timing with 3 attempts, got %+v", steps)
+	}
+}
+
+func TestRetriedSteps_KeepsEvidenceOfTheRecordedAttempt(t *testing.T) {
+	cfg := setBasicConfig()
+	catalog := getTestCatalogWithRequirements()
+	v := &EvaluationOrchestrator{
+		config:            cfg,
+		referenceCatalogs: map[string]*gemara.ControlCatalog{catalog.Metadata.Id: catalog},
+	}
+	calls := 0
+	step := func(ctx context.Context, _ testPayload) (gemara.Result, string, gemara.ConfidenceLevel) {
+		calls++
+		AttachEvidence(ctx, gemara.Evidence{Type: "api-response", Description: fmt.Sprintf("attempt %d", calls)})
+		if calls < 3 {
+			return gemara.Unknown, "upstream returned 502", gemara.Low
+		}
+		return gemara.Passed, "branch protection is enabled", gemara.High
+	}
+	if err := AddEvaluationSuiteContext(v, catalog.Metadata.Id, nil, map[string][]ContextAssessmentStep[testPayload]{
+		"CCC.Core.C01.TR01": {step},
+	}); err != nil {
+		t.Fatalf("registering suite: %v", err)
+	}
+	suite := v.possibleSuites[0]
+	suite.payload = testPayload{}
+	suite.retryPolicy = RetryPolicy{Attempts: 3}
+	if err := suite.Evaluate("retried-evidence"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	log := suite.EvaluationLog.Evaluations[0].AssessmentLogs[0]
+	if log.Result != gemara.Passed || calls != 3 {
+		t.Fatalf("got %s after %d calls, want Passed after 3", log.Result, calls)
+	}
+	if len(log.Evidence) != 1 {
+		t.Fatalf("got %d pieces of evidence, want only the recorded attempt's: %+v", len(log.Evidence), log.Evidence)
+	}
+	if got := log.Evidence[0]; got.Description != "attempt 3" || got.Id != "CCC.Core.C01.TR01-evidence-1" {
+		t.Errorf("got evidence %q (%s), want attempt 3 numbered from 1", got.Description, got.Id)
+	}
+}
@@ -0,0 +1,71 @@
+package pluginkit
+
+import (
+	"encoding/json"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/gemaraproj/go-gemara/gemaraconv"
+)
+
+// sarifReport re-reads the gemara SARIF export to add what it leaves out:
+// the evidence of each assessment, carried in the result's property bag.
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    gemaraconv.Tool `json:"tool"`
+	Results []sarifResult   `json:"results,omitempty"`
+}
+
+type sarifResult struct {
+	gemaraconv.ResultEntry
+	Properties *sarifProperties `json:"properties,omitempty"`
+}
+
+type sarifProperties struct {
+	Evidence []gemara.Evidence `json:"evidence"`
+}
+
+// marshalSARIF converts the suite's EvaluationLog to SARIF. When any
+// assessment carries evidence, the export is rewritten with the evidence on
+// its result; otherwise it is returned as gemara produced it.
+func (e *EvaluationSuite) marshalSARIF() ([]byte, error) {
+	converted, err := gemaraconv.EvaluationLog(e.EvaluationLog).ToSARIF(gemaraconv.WithCatalog(e.catalog))
+	if err != nil {
+		return nil, err
+	}
+
+	// gemara writes one result per assessment, in log order, skipping those
+	// that were not run or not applicable.
+	var evidence [][]gemara.Evidence
+	var attached bool
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		for _, log := range evaluation.AssessmentLogs {
+			if log == nil || log.Result == gemara.NotRun || log.Result == gemara.NotApplicable {
+				continue
+			}
+			evidence = append(evidence, log.Evidence)
+			attached = attached || len(log.Evidence) > 0
+		}
+	}
+	if !attached {
+		return converted, nil
+	}
+
+	var report sarifReport
+	if err := json.Unmarshal(converted, &report); err != nil {
+		return nil, err
+	}
+	if len(report.Runs) != 1 || len(report.Runs[0].Results) != len(evidence) {
+		return converted, nil // not the layout expected; keep gemara's export intact
+	}
+	for i, items := range evidence {
+		if len(items) > 0 {
+			report.Runs[0].Results[i].Properties = &sarifProperties{Evidence: items}
+		}
+	}
+	return json.Marshal(report)
+}
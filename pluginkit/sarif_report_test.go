@@ -0,0 +1,49 @@
+package pluginkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara/gemaraconv"
+)
+
+func TestMarshalSARIF(t *testing.T) {
+	tests := []struct {
+		name         string
+		step         ContextAssessmentStep[testPayload]
+		wantEvidence int
+	}{
+		{name: "without evidence", step: contextStep_Pass},
+		{name: "with evidence", step: contextStep_AttachesEvidence, wantEvidence: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := evidenceSuite(t, 0, tt.step)
+
+			data, err := suite.marshalSARIF()
+			if err != nil {
+				t.Fatalf("marshalSARIF failed: %v", err)
+			}
+			if tt.wantEvidence == 0 {
+				plain, _ := gemaraconv.EvaluationLog(suite.EvaluationLog).ToSARIF(gemaraconv.WithCatalog(suite.catalog))
+				if !bytes.Equal(data, plain) {
+					t.Error("expected the gemara export unchanged when there is no evidence")
+				}
+				return
+			}
+
+			var report sarifReport
+			if err := json.Unmarshal(data, &report); err != nil {
+				t.Fatalf("SARIF output is not valid JSON: %v", err)
+			}
+			results := report.Runs[0].Results
+			if len(results) != 1 || results[0].RuleID != "CCC.Core.C01.TR01" {
+				t.Fatalf("expected the one result of CCC.Core.C01.TR01, got %+v", results)
+			}
+			if results[0].Properties == nil || len(results[0].Properties.Evidence) != tt.wantEvidence {
+				t.Errorf("expected %d pieces of evidence on the result, got %+v", tt.wantEvidence, results[0].Properties)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package pluginkit
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+// AddTestSuite declares a named set of requirement ids (e.g. "quick",
+// "full", "invasive-only") that a run can select with --test-suites. Only the
+// requirements in the selected set are executed; the rest of each catalog is
+// recorded as not run. Declaring "default" replaces the built-in default,
+// which runs every requirement.
+func (v *EvaluationOrchestrator) AddTestSuite(name string, requirementIds []string) error {
+	if name == "" {
+		return BAD_TEST_SUITE(v.PluginName, "test suite name cannot be empty", "ats10")
+	}
+	if len(requirementIds) == 0 {
+		return BAD_TEST_SUITE(v.PluginName, fmt.Sprintf("test suite '%s' lists no requirements", name), "ats20")
+	}
+	if _, exists := v.testSuites[name]; exists {
+		return BAD_TEST_SUITE(v.PluginName, fmt.Sprintf("duplicate test suite name: %s", name), "ats30")
+	}
+	if v.testSuites == nil {
+		v.testSuites = make(map[string][]string)
+	}
+	v.testSuites[name] = slices.Clone(requirementIds)
+	return nil
+}
+
+// selectTestSuite resolves the configured test suite to the requirement ids
+// it runs, or nil to run every requirement. It also records the name on the
+// results envelope.
+func (v *EvaluationOrchestrator) selectTestSuite() (map[string]bool, error) {
+	name := v.config.TestSuite
+	if name == "" {
+		name = config.DefaultTestSuite
+	}
+	v.TestSuite = name
+
+	requirementIds, ok := v.testSuites[name]
+	if !ok {
+		if name == config.DefaultTestSuite {
+			return nil, nil
+		}
+		available := []string{config.DefaultTestSuite}
+		for declared := range v.testSuites {
+			if declared != config.DefaultTestSuite {
+				available = append(available, declared)
+			}
+		}
+		sort.Strings(available[1:])
+		return nil, UNKNOWN_TEST_SUITE(name, available, "sts10")
+	}
+
+	defined := make(map[string]bool)
+	for _, suite := range v.possibleSuites {
+		for _, control := range suite.catalog.Controls {
+			for _, requirement := range control.AssessmentRequirements {
+				defined[requirement.Id] = true
+			}
+		}
+	}
+	selected := make(map[string]bool, len(requirementIds))
+	for _, id := range requirementIds {
+		selected[id] = true
+		if !defined[id] {
+			v.config.Logger.Warn("test suite lists a requirement no catalog defines", "test-suite", name, "requirement", id)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,117 @@
+package pluginkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gemaraproj/go-gemara"
+)
+
+func TestEvaluationOrchestrator_AddTestSuite(t *testing.T) {
+	orchestrator := &EvaluationOrchestrator{PluginName: "test-plugin"}
+	if err := orchestrator.AddTestSuite("quick", []string{"CCC.Core.C00.TR01"}); err != nil {
+		t.Fatalf("AddTestSuite failed: %v", err)
+	}
+	tests := []struct {
+		name    string
+		suite   string
+		reqs    []string
+		wantMod string
+	}{
+		{name: "empty name", reqs: []string{"A"}, wantMod: "ats10"},
+		{name: "no requirements", suite: "full", wantMod: "ats20"},
+		{name: "duplicate", suite: "quick", reqs: []string{"A"}, wantMod: "ats30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := orchestrator.AddTestSuite(tt.suite, tt.reqs)
+			if err == nil || !strings.Contains(err.Error(), tt.wantMod) || !errors.Is(err, ErrDevBug) {
+				t.Errorf("expected a %s dev-bug error, got %v", tt.wantMod, err)
+			}
+		})
+	}
+}
+
+func TestEvaluationOrchestrator_Mobilize_TestSuite(t *testing.T) {
+	catalog := getTestCatalogWithControls(3)
+	steps := map[string][]gemara.AssessmentStep{
+		"CCC.Core.C00.TR01": {step_Pass},
+		"CCC.Core.C01.TR01": {step_Fail},
+		"CCC.Core.C02.TR01": {step_Pass},
+	}
+	tests := []struct {
+		name       string
+		testSuite  string
+		declared   map[string][]string
+		wantResult []gemara.Result
+		wantErr    string
+	}{
+		{
+			name:       "built-in default runs everything",
+			testSuite:  "default",
+			declared:   map[string][]string{"quick": {"CCC.Core.C00.TR01"}},
+			wantResult: []gemara.Result{gemara.Passed, gemara.Failed, gemara.Passed},
+		},
+		{
+			name:       "named suite runs only its requirements",
+			testSuite:  "quick",
+			declared:   map[string][]string{"quick": {"CCC.Core.C00.TR01", "CCC.Core.C02.TR01"}},
+			wantResult: []gemara.Result{gemara.Passed, gemara.NotRun, gemara.Passed},
+		},
+		{
+			name:       "declared default replaces the built-in one",
+			testSuite:  "default",
+			declared:   map[string][]string{"default": {"CCC.Core.C01.TR01"}},
+			wantResult: []gemara.Result{gemara.NotRun, gemara.Failed, gemara.NotRun},
+		},
+		{
+			name:      "unknown suite",
+			testSuite: "full",
+			declared:  map[string][]string{"quick": {"CCC.Core.C00.TR01"}},
+			wantErr:   "requested=full available=[default quick]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setBasicConfig()
+			cfg.Policy.ControlCatalogs = []string{catalog.Metadata.Id}
+			cfg.Write = false
+			cfg.TestSuite = tt.testSuite
+			orchestrator := &EvaluationOrchestrator{
+				ServiceName:    "test-service",
+				PluginName:     "test-plugin",
+				config:         cfg,
+				possibleSuites: []*EvaluationSuite{{CatalogId: catalog.Metadata.Id, catalog: catalog, steps: steps, config: cfg}},
+			}
+			for name, reqs := range tt.declared {
+				if err := orchestrator.AddTestSuite(name, reqs); err != nil {
+					t.Fatalf("AddTestSuite failed: %v", err)
+				}
+			}
+
+			err := orchestrator.Mobilize()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Mobilize failed: %v", err)
+			}
+			if orchestrator.TestSuite != tt.testSuite {
+				t.Errorf("envelope test suite = %q, want %q", orchestrator.TestSuite, tt.testSuite)
+			}
+			for i, evaluation := range orchestrator.Evaluation_Suites[0].EvaluationLog.Evaluations {
+				assessment := evaluation.AssessmentLogs[0]
+				if assessment.Result != tt.wantResult[i] {
+					t.Errorf("%s: got %s, want %s", assessment.Requirement.EntryId, assessment.Result, tt.wantResult[i])
+				}
+				if assessment.Result == gemara.NotRun && assessment.Message != `not in test suite "`+tt.testSuite+`"` {
+					t.Errorf("%s: unexpected message %q", assessment.Requirement.EntryId, assessment.Message)
+				}
+			}
+		})
+	}
+}
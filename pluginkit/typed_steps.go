@@ -2,6 +2,7 @@ package pluginkit
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/gemaraproj/go-gemara"
 )
@@ -70,7 +71,7 @@ func AddEvaluationSuiteTyped[S ~func(T) (gemara.Result, string, gemara.Confidenc
 	v *EvaluationOrchestrator, catalogId string, loader DataLoader, steps map[string][]S,
 ) error {
 	adapted, names := adaptTypedSteps[S, T](steps)
-	return v.addEvaluationSuiteNamed(catalogId, loader, adapted, names)
+	return v.addEvaluationSuiteNamed(catalogId, loader, registeredSteps{steps: adapted, names: names, payloadType: reflect.TypeFor[T]()})
 }
 
 // AddEvaluationSuiteTypedForAllCatalogs is AddEvaluationSuiteTyped applied to
@@ -84,7 +85,7 @@ func AddEvaluationSuiteTypedForAllCatalogs[S ~func(T) (gemara.Result, string, ge
 	}
 	adapted, names := adaptTypedSteps[S, T](steps)
 	for catalogId := range v.referenceCatalogs {
-		if err := v.addEvaluationSuiteNamed(catalogId, loader, adapted, names); err != nil {
+		if err := v.addEvaluationSuiteNamed(catalogId, loader, registeredSteps{steps: adapted, names: names, payloadType: reflect.TypeFor[T]()}); err != nil {
 			return err
 		}
 	}
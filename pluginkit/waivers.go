@@ -0,0 +1,98 @@
+package pluginkit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/privateerproj/privateer-sdk/config"
+)
+
+// WaivedAssessment records a configured waiver that matched an assessment in
+// the suite. The assessment keeps its real result; an active waiver only keeps
+// that result from failing the run, while an expired one is listed so the
+// lapse is visible.
+type WaivedAssessment struct {
+	Control       string        `json:"control" yaml:"control"`
+	Requirement   string        `json:"requirement" yaml:"requirement"`
+	Result        gemara.Result `json:"result" yaml:"result"`
+	Justification string        `json:"justification" yaml:"justification"`
+	Approver      string        `json:"approver" yaml:"approver"`
+	Expires       string        `json:"expires" yaml:"expires"`
+	Expired       bool          `json:"expired" yaml:"expired"`
+}
+
+// applyWaiver matches a completed assessment against the configured waivers
+// and reports whether an active waiver now covers it. Only results that would
+// fail the run can be waived. A covered assessment's message is prefixed with
+// the waiver so the acceptance is visible wherever the message is shown.
+func (e *EvaluationSuite) applyWaiver(controlId string, assessment *gemara.AssessmentLog, now time.Time) bool {
+	switch assessment.Result {
+	case gemara.Passed, gemara.NotRun, gemara.NotApplicable:
+		return false
+	}
+	waiver, ok := e.findWaiver(controlId, assessment.Requirement.EntryId, now)
+	if !ok {
+		return false
+	}
+	active := waiver.Active(now)
+	e.Waivers = append(e.Waivers, WaivedAssessment{
+		Control:       controlId,
+		Requirement:   assessment.Requirement.EntryId,
+		Result:        assessment.Result,
+		Justification: waiver.Justification,
+		Approver:      waiver.Approver,
+		Expires:       waiver.Expires.UTC().Format(time.RFC3339),
+		Expired:       !active,
+	})
+	if !active {
+		e.config.Logger.Warn("waiver has expired; the requirement fails again",
+			"requirement", assessment.Requirement.EntryId, "approver", waiver.Approver, "expired", waiver.Expires)
+		return false
+	}
+	assessment.Message = fmt.Sprintf("[waived by %s until %s: %s] %s",
+		waiver.Approver, waiver.Expires.UTC().Format(time.RFC3339), waiver.Justification, assessment.Message)
+	return true
+}
+
+// findWaiver returns the waiver covering the requirement, preferring an active
+// one when several match.
+func (e *EvaluationSuite) findWaiver(controlId, requirementId string, now time.Time) (config.Waiver, bool) {
+	var found config.Waiver
+	var ok bool
+	for _, waiver := range e.config.Waivers {
+		if !waiver.Matches(e.CatalogId, controlId, requirementId) {
+			continue
+		}
+		if waiver.Active(now) {
+			return waiver, true
+		}
+		if !ok {
+			found, ok = waiver, true
+		}
+	}
+	return found, ok
+}
+
+// unwaivedResult is the suite's result with actively waived assessments left
+// out, which is what decides the run's exit code.
+func (e *EvaluationSuite) unwaivedResult() gemara.Result {
+	waived := make(map[string]bool)
+	for _, w := range e.Waivers {
+		if !w.Expired {
+			waived[w.Control+"/"+w.Requirement] = true
+		}
+	}
+	if len(waived) == 0 {
+		return e.Result
+	}
+	result := gemara.NotRun
+	for _, evaluation := range e.EvaluationLog.Evaluations {
+		for _, assessment := range evaluation.AssessmentLogs {
+			if !waived[evaluation.Control.EntryId+"/"+assessment.Requirement.EntryId] {
+				result = gemara.UpdateAggregateResult(result, assessment.Result)
+			}
+		}
+	}
+	return result
+}
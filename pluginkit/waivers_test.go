@@ -0,0 +1,101 @@
+package pluginkit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gemaraproj/go-gemara"
+	"github.com/privateerproj/privateer-sdk/config"
+	"github.com/privateerproj/privateer-sdk/shared"
+)
+
+func TestEvaluationSuite_Waivers(t *testing.T) {
+	waiver := config.Waiver{
+		Catalog:       "CCC.Parallel",
+		Control:       "CCC.Core.C01",
+		Requirement:   "CCC.Core.C01.TR01",
+		Justification: "accepted until the bucket migration",
+		Approver:      "security-lead",
+	}
+	tests := []struct {
+		name        string
+		expires     time.Time
+		otherCtrl   bool
+		wantWaived  bool
+		wantExpired bool
+		wantExit    int
+		wantCounts  string // passed/failed/waived evaluations
+	}{
+		{name: "active waiver does not fail the run", expires: time.Now().Add(time.Hour), wantWaived: true, wantExit: shared.TestPass, wantCounts: "1/0/1"},
+		{name: "expired waiver fails again", expires: time.Now().Add(-time.Hour), wantExpired: true, wantExit: shared.TestFail, wantCounts: "1/1/0"},
+		{name: "waiver for another control is ignored", expires: time.Now().Add(time.Hour), otherCtrl: true, wantExit: shared.TestFail, wantCounts: "1/1/0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setBasicConfig()
+			w := waiver
+			w.Expires = tt.expires
+			if tt.otherCtrl {
+				w.Control, w.Requirement = "CCC.Core.C00", ""
+			}
+			cfg.Waivers = []config.Waiver{w}
+			catalog := getTestCatalogWithControls(2)
+			suite := &EvaluationSuite{
+				CatalogId: catalog.Metadata.Id,
+				catalog:   catalog,
+				config:    cfg,
+				steps: map[string][]gemara.AssessmentStep{
+					"CCC.Core.C00.TR01": {step_Pass},
+					"CCC.Core.C01.TR01": {step_Fail},
+				},
+			}
+			if err := suite.Evaluate("waivers"); err != nil {
+				t.Fatalf("Evaluate failed: %v", err)
+			}
+
+			if suite.Result != gemara.Failed {
+				t.Errorf("the suite keeps its real result, got %s", suite.Result)
+			}
+			failed := suite.EvaluationLog.Evaluations[1].AssessmentLogs[0]
+			if failed.Result != gemara.Failed {
+				t.Errorf("a waived assessment keeps its real result, got %s", failed.Result)
+			}
+			if got := strings.HasPrefix(failed.Message, "[waived by security-lead until "); got != tt.wantWaived {
+				t.Errorf("waived message prefix = %v, want %v: %q", got, tt.wantWaived, failed.Message)
+			}
+			if recorded := len(suite.Waivers) == 1; recorded != (tt.wantWaived || tt.wantExpired) {
+				t.Fatalf("unexpected waiver records: %+v", suite.Waivers)
+			}
+			if len(suite.Waivers) == 1 && (suite.Waivers[0].Expired != tt.wantExpired || suite.Waivers[0].Result != gemara.Failed) {
+				t.Errorf("unexpected waiver record: %+v", suite.Waivers[0])
+			}
+
+			if got := fmt.Sprintf("%d/%d/%d", suite.evalSuccesses, suite.evalFailures, suite.evalWaived); got != tt.wantCounts {
+				t.Errorf("passed/failed/waived evaluations = %s, want %s", got, tt.wantCounts)
+			}
+
+			orchestrator := &EvaluationOrchestrator{Evaluation_Suites: []*EvaluationSuite{suite}}
+			if got := ExitCodeFor(orchestrator, nil); got != tt.wantExit {
+				t.Errorf("ExitCodeFor = %d, want %d", got, tt.wantExit)
+			}
+		})
+	}
+}
+
+func TestEvaluationSuite_WaiverDoesNotHideOtherFailures(t *testing.T) {
+	suite := &EvaluationSuite{
+		Result:  gemara.Failed,
+		Waivers: []WaivedAssessment{{Control: "C01", Requirement: "C01.TR01"}},
+		EvaluationLog: gemara.EvaluationLog{Evaluations: []*gemara.ControlEvaluation{
+			{Control: gemara.EntryMapping{EntryId: "C01"}, AssessmentLogs: []*gemara.AssessmentLog{
+				{Requirement: gemara.EntryMapping{EntryId: "C01.TR01"}, Result: gemara.Failed},
+				{Requirement: gemara.EntryMapping{EntryId: "C01.TR02"}, Result: gemara.NeedsReview},
+			}},
+		}},
+	}
+	if got := suite.unwaivedResult(); got != gemara.NeedsReview {
+		t.Errorf("unwaivedResult = %s, want %s", got, gemara.NeedsReview)
+	}
+}